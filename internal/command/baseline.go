@@ -0,0 +1,123 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/i18n"
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+// baselineDelta compares a value of the estimation with the one of its baseline
+type baselineDelta struct {
+	Baseline float64
+	Current  float64
+}
+
+// Growth returns the relative growth over the baseline, in percent, and false when
+// the baseline is zero
+func (d baselineDelta) Growth() (float64, bool) {
+	if d.Baseline == 0 {
+		return 0, false
+	}
+	return (d.Current - d.Baseline) / d.Baseline * 100, true
+}
+
+// Exceeds returns true if the value grew over the baseline beyond the threshold, in percent
+func (d baselineDelta) Exceeds(threshold float64) bool {
+	if threshold < 0 {
+		return false
+	}
+	growth, ok := d.Growth()
+	if !ok {
+		return d.Current > 0
+	}
+	return growth > threshold
+}
+
+// String formats the delta as "baseline -> current (+growth%)"
+func (d baselineDelta) String() string {
+	delta := fmt.Sprintf("%.2f -> %.2f", d.Baseline, d.Current)
+	if growth, ok := d.Growth(); ok {
+		delta += fmt.Sprintf(" (%+.1f%%)", growth)
+	}
+	return delta
+}
+
+// baselineComparison compares the project mean and 99.7% maximum cost of an estimation
+// with the ones of a baseline estimation
+type baselineComparison struct {
+	File     string
+	TimeUnit string
+	Currency string
+	Mean     baselineDelta
+	// Cost is left empty for story points, which have no cost
+	Cost *baselineDelta
+}
+
+// compareToBaseline loads the baseline file and compares the estimation with it, each
+// estimation being calculated with its own parameters applied to the base configuration
+func compareToBaseline(s store.Store, file string, baseConfig *model.Config, estimation *model.Estimation) (*baselineComparison, error) {
+	baseline, err := s.LoadEstimation(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+
+	baselineConfig := baseConfig.WithParams(baseline.Params)
+	config := baseConfig.WithParams(estimation.Params)
+
+	comparison := &baselineComparison{
+		File:     file,
+		TimeUnit: config.TimeUnit.Acronym,
+		Currency: config.Currency,
+	}
+
+	baselineEst := stats.CalculateProjectEstimationFor(baseline, baselineConfig)
+	projectEst := stats.CalculateProjectEstimationFor(estimation, config)
+	comparison.Mean = baselineDelta{Baseline: baselineEst.WeightedMean, Current: projectEst.WeightedMean}
+
+	if config.HasCosts() {
+		comparison.Cost = &baselineDelta{
+			Baseline: maxCost(baseline, baselineConfig, baselineEst),
+			Current:  maxCost(estimation, config, projectEst),
+		}
+	}
+
+	return comparison, nil
+}
+
+// maxCost returns the maximum cost of the estimation at the 99.7% confidence level
+func maxCost(estimation *model.Estimation, config *model.Config, projectEst stats.EstimationResult) float64 {
+	distribution := stats.CalculateCategoryDistribution(estimation, config)
+	confidence := stats.ResolveConfidenceLevel(config, stats.Confidence997)
+	costs := stats.CalculateMinMaxCostsFrom(projectEst, distribution, stats.CalculateFixedCost(estimation), config, confidence)
+	return costs.Max.TotalCost
+}
+
+// print prints the comparison, flagging the values grown beyond the threshold, and returns
+// an error if any did
+func (c *baselineComparison) print(tr *i18n.Translator, threshold float64) error {
+	fmt.Println(tr.Sprintf("Baseline Comparison (%s):", c.File))
+
+	exceeded := false
+	printDelta := func(label string, delta baselineDelta, unit string) {
+		line := fmt.Sprintf("  %-17s %s %s", label, delta, unit)
+		if delta.Exceeds(threshold) {
+			line += " " + tr.Sprintf("[grew beyond %g%%]", threshold)
+			exceeded = true
+		}
+		fmt.Println(line)
+	}
+
+	printDelta(tr.T("Mean:"), c.Mean, c.TimeUnit)
+	if c.Cost != nil {
+		printDelta(tr.T("99.7% cost:"), *c.Cost, c.Currency)
+	}
+
+	if exceeded {
+		return withCode(errCodeBaselineExceeded, fmt.Errorf("estimation grew beyond the %g%% threshold over the baseline %s", threshold, c.File))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+// loadCombinedEstimation loads several estimation files into a single in-memory estimation,
+// along with the configuration merging their parameters. Nothing is written.
+//
+// Tasks keep their IDs unless already taken by a previous file, in which case the ID is
+// prefixed with the file position (e.g. "2-t1"). Categories defined by several files with
+// different rates keep the first definition and are reported as warnings. Likewise, the
+// time unit, currency, rounding, markup and discount of the first file apply to the whole
+// report, the files using other ones being reported.
+func loadCombinedEstimation(s store.Store, files []string, config *model.Config) (*model.Estimation, *model.Config, []string, error) {
+	combined := &model.Estimation{
+		Ordering: []model.TaskID{},
+		Tasks:    make(map[model.TaskID]*model.Task),
+	}
+	merged := config.Clone()
+	merged.TaskCategories = make(map[string]model.TaskCategory)
+
+	var labels, warnings []string
+	categoryFiles := make(map[string]string)
+
+	for i, file := range files {
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load estimation %s: %w", file, err)
+		}
+		labels = append(labels, estimation.Label)
+
+		effective := config.WithParams(estimation.Params)
+		if i == 0 {
+			merged.TimeUnit = effective.TimeUnit
+			merged.Currency = effective.Currency
+			merged.RoundUpEstimations = effective.RoundUpEstimations
+			merged.Markup = effective.Markup
+			merged.Discount = effective.Discount
+		} else {
+			if effective.TimeUnit.Acronym != merged.TimeUnit.Acronym {
+				warnings = append(warnings, fmt.Sprintf("%s uses the time unit '%s' instead of '%s'", file, effective.TimeUnit.Acronym, merged.TimeUnit.Acronym))
+			}
+			if effective.Currency != merged.Currency {
+				warnings = append(warnings, fmt.Sprintf("%s uses the currency '%s' instead of '%s'", file, effective.Currency, merged.Currency))
+			}
+			if effective.RoundUpEstimations != merged.RoundUpEstimations {
+				warnings = append(warnings, fmt.Sprintf("%s sets roundUpEstimations to %t instead of %t", file, effective.RoundUpEstimations, merged.RoundUpEstimations))
+			}
+			if effective.Markup != merged.Markup {
+				warnings = append(warnings, fmt.Sprintf("%s uses a %g%% markup instead of %g%%", file, effective.Markup, merged.Markup))
+			}
+			if effective.Discount != merged.Discount {
+				warnings = append(warnings, fmt.Sprintf("%s uses a %g%% discount instead of %g%%", file, effective.Discount, merged.Discount))
+			}
+		}
+
+		for id, cat := range effective.TaskCategories {
+			previous, ok := merged.TaskCategories[id]
+			if !ok {
+				merged.TaskCategories[id] = cat
+				categoryFiles[id] = file
+				continue
+			}
+			if previous.CostPerTimeUnit != cat.CostPerTimeUnit || previous.Currency != cat.Currency {
+				warnings = append(warnings, fmt.Sprintf("category '%s' costs %s in %s but %s in %s, keeping the first rate",
+					id, previous.FormatRate(), categoryFiles[id], cat.FormatRate(), file))
+			}
+		}
+
+		for _, task := range estimation.GetOrderedTasks() {
+			task := task.Clone()
+			if _, taken := combined.Tasks[task.ID]; taken {
+				task.ID = model.TaskID(fmt.Sprintf("%d-%s", i+1, task.ID))
+			}
+			combined.Tasks[task.ID] = task
+			combined.Ordering = append(combined.Ordering, task.ID)
+		}
+
+		if combined.CreatedAt.IsZero() || estimation.CreatedAt.Before(combined.CreatedAt) {
+			combined.CreatedAt = estimation.CreatedAt
+		}
+		if estimation.UpdatedAt.After(combined.UpdatedAt) {
+			combined.UpdatedAt = estimation.UpdatedAt
+		}
+	}
+
+	combined.Label = strings.Join(labels, " + ")
+
+	return combined, merged, warnings, nil
+}
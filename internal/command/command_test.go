@@ -0,0 +1,179 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// runCommand runs the command line given by args against the store, restoring the store
+// factory and the flags of every command once the test is done
+func runCommand(t *testing.T, s store.Store, args ...string) error {
+	t.Helper()
+
+	previous := newStore
+	newStore = func(string) store.Store { return s }
+	t.Cleanup(func() {
+		newStore = previous
+		resetFlags(rootCmd)
+		argsParsed = false
+	})
+
+	rootCmd.SetArgs(args)
+	_, err := rootCmd.ExecuteC()
+	return err
+}
+
+// resetFlags sets the flags of the command and of its subcommands back to their defaults,
+// cobra keeping the values of the previous execution
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if value, ok := flag.Value.(pflag.SliceValue); ok {
+			value.Replace(nil)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// newTestStore returns a memory store holding an estimation with a single task
+func newTestStore(t *testing.T, file string, estimations model.Estimations) (*store.MemoryStore, *model.Task) {
+	t.Helper()
+
+	s := store.NewMemoryStore()
+	estimation := model.NewEstimation("Project")
+	task := model.NewTask("Login", "development")
+	task.Estimations = estimations
+	estimation.AddTask(task)
+
+	if err := s.SaveEstimation(file, estimation); err != nil {
+		t.Fatalf("SaveEstimation() error = %v", err)
+	}
+	return s, task
+}
+
+// loadTask loads the task with the given ID from the estimation of the store
+func loadTask(t *testing.T, s store.Store, file string, id model.TaskID) *model.Task {
+	t.Helper()
+
+	estimation, err := s.LoadEstimation(file)
+	if err != nil {
+		t.Fatalf("LoadEstimation() error = %v", err)
+	}
+	task, ok := estimation.Tasks[id]
+	if !ok {
+		t.Fatalf("task %s not found", id)
+	}
+	return task
+}
+
+func TestTaskAdd(t *testing.T) {
+	const file = "project.estimation.yml"
+	s := store.NewMemoryStore()
+
+	if err := runCommand(t, s, "task", "add", file, "Login", "-l", "3"); err != nil {
+		t.Fatalf("task add error = %v", err)
+	}
+
+	estimation, err := s.LoadEstimation(file)
+	if err != nil {
+		t.Fatalf("LoadEstimation() error = %v", err)
+	}
+	tasks := estimation.GetOrderedTasks()
+	if len(tasks) != 1 {
+		t.Fatalf("tasks = %d, want 1", len(tasks))
+	}
+	if want := (model.Estimations{Optimistic: 2, Likely: 3, Pessimistic: 4}); tasks[0].Estimations != want {
+		t.Errorf("estimations = %+v, want %+v", tasks[0].Estimations, want)
+	}
+}
+
+func TestTaskScale(t *testing.T) {
+	const file = "project.estimation.yml"
+
+	tests := []struct {
+		name string
+		args []string
+		want model.Estimations
+	}{
+		{
+			name: "scaled",
+			args: []string{"task", "scale", file, "--factor", "1.5"},
+			want: model.Estimations{Optimistic: 0, Likely: 4.5, Pessimistic: 6},
+		},
+		{
+			name: "dry run",
+			args: []string{"--dry-run", "task", "scale", file, "--factor", "1.5"},
+			want: model.Estimations{Optimistic: 0, Likely: 3, Pessimistic: 4},
+		},
+		{
+			name: "factor of one",
+			args: []string{"task", "scale", file, "--factor", "1"},
+			want: model.Estimations{Optimistic: 0, Likely: 3, Pessimistic: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, task := newTestStore(t, file, model.Estimations{Optimistic: 0, Likely: 3, Pessimistic: 4})
+
+			if err := runCommand(t, s, tt.args...); err != nil {
+				t.Fatalf("task scale error = %v", err)
+			}
+
+			if got := loadTask(t, s, file, task.ID).Estimations; got != tt.want {
+				t.Errorf("estimations = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortfolioRefusesMixedTimeUnits(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	hours := model.NewEstimation("Backend")
+	hours.Params = &model.EstimationParams{TimeUnit: &model.TimeUnit{Label: "man-hour", Acronym: "mh"}}
+	for file, estimation := range map[string]*model.Estimation{
+		"estimations/web.estimation.yml":          model.NewEstimation("Web"),
+		"estimations/team/backend.estimation.yml": hours,
+	} {
+		if err := s.SaveEstimation(file, estimation); err != nil {
+			t.Fatalf("SaveEstimation() error = %v", err)
+		}
+	}
+
+	err := runCommand(t, s, "portfolio", "estimations")
+	if err == nil || !strings.Contains(err.Error(), "time unit 'md'") {
+		t.Errorf("portfolio error = %v, want a time unit mismatch", err)
+	}
+}
+
+func TestRootLastIsOnlyForTheMCPServer(t *testing.T) {
+	err := runCommand(t, store.NewMemoryStore(), "--root", "last", "list", "estimations")
+	if err == nil || !strings.Contains(err.Error(), "only supported by 'mcp server'") {
+		t.Errorf("list --root last error = %v, want it refused", err)
+	}
+}
+
+func TestMissingRequiredFlagIsAUsageError(t *testing.T) {
+	s, _ := newTestStore(t, "project.estimation.yml", model.Estimations{Optimistic: 0, Likely: 3, Pessimistic: 4})
+
+	err := runCommand(t, s, "task", "scale", "project.estimation.yml")
+	if err == nil || !strings.Contains(err.Error(), `"factor" not set`) {
+		t.Fatalf("task scale error = %v, want the missing factor", err)
+	}
+	if argsParsed {
+		t.Errorf("argsParsed = true, want the error reported with the usage")
+	}
+}
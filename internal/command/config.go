@@ -3,7 +3,10 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/store"
@@ -22,15 +25,19 @@ var configCmd = &cobra.Command{
 var configInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize configuration file",
-	Long:  `Create a default configuration file in the config directory.`,
+	Long: `Create a default configuration file: the --config file, the $GUESSTIMATE_CONFIG
+one or .guesstimate.yml in the current directory, even when a parent directory has one.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		s := getStore()
-
-		// Check if config already exists
 		configPath := configFile
+		if configPath == "" {
+			configPath = os.Getenv(store.ConfigEnvVar)
+		}
 		if configPath == "" {
 			configPath = store.DefaultConfigFile
 		}
+		s := getStoreFor(configPath)
+
+		// Check if config already exists
 		if _, err := os.Stat(configPath); err == nil {
 			force, _ := cmd.Flags().GetBool("force")
 			if !force {
@@ -41,11 +48,11 @@ var configInitCmd = &cobra.Command{
 		// Create default config
 		config := model.DefaultConfig()
 
-		if err := s.SaveConfig(config); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if err := saveConfig(s, config); err != nil {
+			return err
 		}
 
-		fmt.Printf("Configuration file created at %s\n", configPath)
+		infof("Configuration file created at %s\n", configPath)
 		return nil
 	},
 }
@@ -81,11 +88,14 @@ var configViewCmd = &cobra.Command{
 		default:
 			fmt.Println("Task Categories:")
 			for id, cat := range config.TaskCategories {
-				fmt.Printf("  %s: %s (%.2f per time unit)\n", id, cat.Label, cat.CostPerTimeUnit)
+				fmt.Printf("  %s: %s (%s per time unit)\n", id, cat.Label, cat.FormatRate())
+				if cat.Description != "" {
+					fmt.Printf("      %s\n", cat.Description)
+				}
 			}
 			fmt.Printf("\nTime Unit: %s (%s)\n", config.TimeUnit.Label, config.TimeUnit.Acronym)
 			fmt.Printf("Currency: %s\n", config.Currency)
-			fmt.Printf("Round Up Estimations: %v\n", config.RoundUpEstimations)
+			fmt.Printf("Round Up Estimations: %v (%s, %s)\n", config.RoundUpEstimations, config.GetRoundingScope(), config.GetRoundingDirection())
 		}
 
 		return nil
@@ -115,23 +125,26 @@ var configCategoryAddCmd = &cobra.Command{
 
 		id := args[0]
 		label := args[1]
-		cost, _ := cmd.Flags().GetFloat64("cost")
-
-		if _, exists := config.TaskCategories[id]; exists {
-			return fmt.Errorf("category with id '%s' already exists", id)
+		cost := config.GetDefaultCostPerTimeUnit()
+		if cmd.Flags().Changed("cost") {
+			cost, _ = cmd.Flags().GetFloat64("cost")
 		}
+		description, _ := cmd.Flags().GetString("description")
+		currency, _ := cmd.Flags().GetString("currency")
 
-		config.TaskCategories[id] = model.TaskCategory{
-			ID:              id,
-			Label:           label,
-			CostPerTimeUnit: cost,
+		if err := config.AddCategory(id, label, cost); err != nil {
+			return fmt.Errorf("category '%s': %w", id, err)
 		}
+		category := config.TaskCategories[id]
+		category.Description = description
+		category.Currency = currency
+		config.TaskCategories[id] = category
 
-		if err := s.SaveConfig(config); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if err := saveConfig(s, config); err != nil {
+			return err
 		}
 
-		fmt.Printf("Category '%s' added successfully\n", id)
+		infof("Category '%s' added successfully\n", id)
 		return nil
 	},
 }
@@ -156,17 +169,111 @@ var configCategoryRemoveCmd = &cobra.Command{
 			return fmt.Errorf("category with id '%s' does not exist", id)
 		}
 
+		// Without any category, tasks can no longer be added
+		if force, _ := cmd.Flags().GetBool("force"); len(config.TaskCategories) == 1 && !force {
+			return fmt.Errorf("category '%s' is the last one, tasks could no longer be added without it (use --force to remove it anyway)", id)
+		}
+
 		delete(config.TaskCategories, id)
 
-		if err := s.SaveConfig(config); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if err := saveConfig(s, config); err != nil {
+			return err
 		}
 
-		fmt.Printf("Category '%s' removed successfully\n", id)
+		infof("Category '%s' removed successfully\n", id)
 		return nil
 	},
 }
 
+// configCategoryListCmd represents the config category list command
+var configCategoryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List task categories",
+	Long:  `List the task categories of the configuration, ordered by ID.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := getStore()
+
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		categories := make([]categoryOutput, 0, len(config.TaskCategories))
+		for _, id := range slices.Sorted(maps.Keys(config.TaskCategories)) {
+			cat := config.TaskCategories[id]
+			categories = append(categories, categoryOutput{
+				ID:              id,
+				Label:           cat.Label,
+				CostPerTimeUnit: cat.CostPerTimeUnit,
+				Currency:        cat.Currency,
+			})
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+
+		switch format {
+		case "json":
+			data, err := json.MarshalIndent(categories, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal categories to JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		default:
+			printCategoryTable(categories, config)
+		}
+
+		return nil
+	},
+}
+
+// categoryOutput is a task category as listed by config category list
+type categoryOutput struct {
+	ID              string  `json:"id"`
+	Label           string  `json:"label"`
+	CostPerTimeUnit float64 `json:"costPerTimeUnit"`
+	Currency        string  `json:"currency,omitempty"`
+}
+
+// printCategoryTable prints the categories as a table, the columns being aligned on
+// their widest value
+func printCategoryTable(categories []categoryOutput, config *model.Config) {
+	rateHeader := fmt.Sprintf("Cost per %s", config.TimeUnit.Acronym)
+	idWidth, labelWidth, rateWidth := len("ID"), len("Label"), len(rateHeader)
+
+	rates := make([]string, len(categories))
+	for i, cat := range categories {
+		currency := cat.Currency
+		if currency == "" {
+			currency = config.Currency
+		}
+		rates[i] = fmt.Sprintf("%.2f %s", cat.CostPerTimeUnit, currency)
+
+		idWidth = max(idWidth, utf8.RuneCountInString(cat.ID))
+		labelWidth = max(labelWidth, utf8.RuneCountInString(cat.Label))
+		rateWidth = max(rateWidth, utf8.RuneCountInString(rates[i]))
+	}
+
+	fmt.Printf("%-*s  %-*s  %*s\n", idWidth, "ID", labelWidth, "Label", rateWidth, rateHeader)
+	for i, cat := range categories {
+		fmt.Printf("%-*s  %-*s  %*s\n", idWidth, cat.ID, labelWidth, cat.Label, rateWidth, rates[i])
+	}
+}
+
+// saveConfig saves the configuration, unless in dry-run mode
+func saveConfig(s store.Store, config *model.Config) error {
+	if dryRun {
+		infof("Dry run: configuration not saved\n")
+		return nil
+	}
+
+	if err := s.SaveConfig(config); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configInitCmd)
@@ -174,8 +281,13 @@ func init() {
 	configCmd.AddCommand(configCategoryCmd)
 	configCategoryCmd.AddCommand(configCategoryAddCmd)
 	configCategoryCmd.AddCommand(configCategoryRemoveCmd)
+	configCategoryCmd.AddCommand(configCategoryListCmd)
 
 	configInitCmd.Flags().BoolP("force", "f", false, "Force overwrite existing configuration")
 	configViewCmd.Flags().StringP("format", "f", "yaml", "Output format (yaml, json)")
-	configCategoryAddCmd.Flags().Float64("cost", 500, "Cost per time unit")
+	configCategoryAddCmd.Flags().Float64("cost", 0, "Cost per time unit (default: defaultCostPerTimeUnit or 500)")
+	configCategoryAddCmd.Flags().String("description", "", "Category description (e.g. blended senior+junior rate)")
+	configCategoryAddCmd.Flags().String("currency", "", "Currency of the category rate (default: configuration currency)")
+	configCategoryListCmd.Flags().StringP("format", "f", "text", "Output format (text, json)")
+	configCategoryRemoveCmd.Flags().Bool("force", false, "Allow removing the last category")
 }
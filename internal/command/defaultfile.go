@@ -0,0 +1,48 @@
+package command
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// estimationFileEnvVar is the environment variable giving the estimation file of the
+// commands run without one
+const estimationFileEnvVar = "GUESSTIMATE_FILE"
+
+// defaultEstimationFile returns the estimation file of the commands run without one,
+// looking in order for the GUESSTIMATE_FILE environment variable and the
+// defaultEstimation configuration. It is empty when none is set.
+func defaultEstimationFile() string {
+	if file := os.Getenv(estimationFileEnvVar); file != "" {
+		return file
+	}
+
+	config, err := getStore().LoadConfig()
+	if err != nil {
+		// The command reports the configuration error itself
+		return ""
+	}
+
+	return config.DefaultEstimation
+}
+
+// optionalFileArgs validates the arguments of a command whose leading estimation file may
+// be omitted when a default one is set, the other cases being left to validate
+func optionalFileArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && defaultEstimationFile() != "" {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+}
+
+// withDefaultFile returns the arguments with the default estimation file when none is given,
+// an explicit file always winning
+func withDefaultFile(args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+	return []string{defaultEstimationFile()}
+}
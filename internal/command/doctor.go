@@ -0,0 +1,154 @@
+package command
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor <file>",
+	Short: "Report and repair structural issues of an estimation",
+	Long: `Check an estimation edited by several tools or by hand for structural issues:
+tasks missing from the ordering, which no report shows, ordering entries without
+task, duplicate ordering entries and tasks with unknown categories.
+
+With --fix, the ordering is repaired: the entries without task and the duplicate
+ones are removed, and the missing tasks are appended sorted by ID. Unknown
+categories are left unchanged as only their rate is missing: add them with
+'config category add' or change the category of their tasks.
+
+The command exits with a non-zero status while any issue remains, including
+unknown categories after a --fix.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		issues := estimation.CheckOrdering()
+		unknownCategories := tasksWithUnknownCategory(estimation, config)
+
+		if issues.IsEmpty() && len(unknownCategories) == 0 {
+			infof("No structural issue found.\n")
+			return nil
+		}
+
+		if len(issues.Unordered) > 0 {
+			fmt.Println("Tasks missing from the ordering:")
+			for _, id := range issues.Unordered {
+				fmt.Printf("  %s (%s)\n", id, estimation.Tasks[id].Label)
+			}
+		}
+		if len(issues.Dangling) > 0 {
+			fmt.Println("Ordering entries without task:")
+			for _, id := range issues.Dangling {
+				fmt.Printf("  %s\n", id)
+			}
+		}
+		if len(issues.Duplicates) > 0 {
+			fmt.Println("Duplicate ordering entries:")
+			for _, id := range issues.Duplicates {
+				fmt.Printf("  %s\n", id)
+			}
+		}
+		if len(unknownCategories) > 0 {
+			fmt.Println("Tasks with unknown categories:")
+			for _, task := range unknownCategories {
+				fmt.Printf("  %s (%s): '%s'\n", task.ID, task.Label, task.Category)
+			}
+		}
+
+		if !issues.IsEmpty() {
+			if !fix {
+				return withCode(errCodeValidationFailed, fmt.Errorf("estimation ordering has %d issue(s), use --fix to repair it",
+					len(issues.Unordered)+len(issues.Dangling)+len(issues.Duplicates)))
+			}
+
+			if err := repairOrdering(cmd, s, file, estimation); err != nil {
+				return err
+			}
+		}
+
+		if n := len(unknownCategories); n > 0 {
+			return withCode(errCodeValidationFailed, fmt.Errorf("%d task(s) have unknown categories, add them with 'config category add' or change the category of the tasks", n))
+		}
+		return nil
+	},
+}
+
+// repairOrdering repairs the ordering of the estimation and saves it, reporting the
+// repairs as would-be changes in dry-run mode
+func repairOrdering(cmd *cobra.Command, s store.Store, file string, estimation *model.Estimation) error {
+	if err := checkUnlocked(cmd, estimation); err != nil {
+		return err
+	}
+
+	original := estimation.Clone()
+	repaired := estimation.NormalizeOrdering()
+
+	// Save estimation
+	if err := saveEstimation(s, file, original, estimation); err != nil {
+		return err
+	}
+
+	if n := len(repaired.Unordered); n > 0 {
+		if dryRun {
+			infof("Would append %d task(s) to the ordering\n", n)
+		} else {
+			infof("Appended %d task(s) to the ordering\n", n)
+		}
+	}
+	if n := len(repaired.Dangling); n > 0 {
+		if dryRun {
+			infof("Would remove %d ordering entries without task\n", n)
+		} else {
+			infof("Removed %d ordering entries without task\n", n)
+		}
+	}
+	if n := len(repaired.Duplicates); n > 0 {
+		if dryRun {
+			infof("Would remove the repeated ordering entries of %d task(s)\n", n)
+		} else {
+			infof("Removed the repeated ordering entries of %d task(s)\n", n)
+		}
+	}
+	return nil
+}
+
+// tasksWithUnknownCategory returns the tasks whose category is not configured, sorted by ID
+func tasksWithUnknownCategory(estimation *model.Estimation, config *model.Config) []*model.Task {
+	var tasks []*model.Task
+	for _, id := range slices.Sorted(maps.Keys(estimation.Tasks)) {
+		if task := estimation.Tasks[id]; !config.HasTaskCategory(task.Category) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "Repair the ordering issues")
+	doctorCmd.Flags().Bool("force", false, "Allow repairing a locked estimation")
+}
@@ -0,0 +1,242 @@
+package command
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+// loadOrCreateEstimation loads an estimation, or creates a new one if it doesn't exist.
+// In dry-run mode, the new estimation is only created in memory.
+func loadOrCreateEstimation(s store.Store, file string) (*model.Estimation, bool, error) {
+	if !dryRun {
+		return s.LoadOrCreateEstimation(file, file)
+	}
+	return loadOrNewEstimation(s, file)
+}
+
+// loadOrNewEstimation loads an estimation, or returns a new in-memory one if it doesn't
+// exist, without creating the file
+func loadOrNewEstimation(s store.Store, file string) (*model.Estimation, bool, error) {
+	estimation, err := s.LoadEstimation(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.NewEstimation(file), true, nil
+		}
+		return nil, false, err
+	}
+
+	return estimation, false, nil
+}
+
+// saveEstimation saves the estimation to the given file. In dry-run mode, the changes
+// compared to the original estimation and the new project totals are printed instead.
+// A nil original means the estimation is new.
+func saveEstimation(s store.Store, file string, original *model.Estimation, estimation *model.Estimation) error {
+	if !dryRun {
+		if err := s.SaveEstimation(file, estimation); err != nil {
+			return fmt.Errorf("failed to save estimation: %w", err)
+		}
+		return nil
+	}
+
+	config, err := s.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	config = config.WithParams(estimation.Params)
+
+	if original == nil {
+		original = &model.Estimation{Tasks: map[model.TaskID]*model.Task{}}
+	}
+
+	changes := describeChanges(original, estimation)
+
+	infof("Dry run: no changes saved to %s\n", file)
+	if len(changes) == 0 {
+		infof("  (no changes)\n")
+	}
+	for _, change := range changes {
+		infof("  %s\n", change)
+	}
+
+	before := stats.CalculateProjectEstimationFor(original, config)
+	after := stats.CalculateProjectEstimationFor(estimation, config)
+	infof("Project totals: %.2f ± %.2f %s -> %.2f ± %.2f %s\n",
+		before.WeightedMean, before.StandardDeviation, config.TimeUnit.Acronym,
+		after.WeightedMean, after.StandardDeviation, config.TimeUnit.Acronym)
+
+	return nil
+}
+
+// formatMaxEstimate formats an optional task cap
+func formatMaxEstimate(maxEstimate *float64) string {
+	if maxEstimate == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%.2f", *maxEstimate)
+}
+
+// formatOptional formats an optional parameter
+func formatOptional[T any](value *T) string {
+	if value == nil {
+		return "none"
+	}
+	return fmt.Sprint(*value)
+}
+
+// formatApproval formats the approval of an estimation
+func formatApproval(estimation *model.Estimation) string {
+	if estimation.ApprovedBy == "" && estimation.ApprovedAt == nil {
+		return "none"
+	}
+	if estimation.ApprovedAt == nil {
+		return fmt.Sprintf("by %q", estimation.ApprovedBy)
+	}
+	return fmt.Sprintf("by %q at %s", estimation.ApprovedBy, estimation.ApprovedAt.Format(time.RFC3339))
+}
+
+// describeParamsChanges lists the differences between two versions of the parameters of an estimation
+func describeParamsChanges(before *model.EstimationParams, after *model.EstimationParams) []string {
+	if before == nil {
+		before = &model.EstimationParams{}
+	}
+	if after == nil {
+		after = &model.EstimationParams{}
+	}
+
+	var changes []string
+
+	for _, id := range slices.Sorted(maps.Keys(before.TaskCategories)) {
+		if _, ok := after.TaskCategories[id]; !ok {
+			changes = append(changes, fmt.Sprintf("- params category %s", id))
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(after.TaskCategories)) {
+		cat := after.TaskCategories[id]
+		previous, ok := before.TaskCategories[id]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+ params category %s %q (%g)", id, cat.Label, cat.CostPerTimeUnit))
+		case previous != cat:
+			changes = append(changes, fmt.Sprintf("~ params category %s: %q (%g) -> %q (%g)", id, previous.Label, previous.CostPerTimeUnit, cat.Label, cat.CostPerTimeUnit))
+		}
+	}
+
+	if formatOptional(before.TimeUnit) != formatOptional(after.TimeUnit) {
+		changes = append(changes, fmt.Sprintf("~ params time unit: %s -> %s", formatOptional(before.TimeUnit), formatOptional(after.TimeUnit)))
+	}
+	if before.Currency != after.Currency {
+		changes = append(changes, fmt.Sprintf("~ params currency: %q -> %q", before.Currency, after.Currency))
+	}
+	if formatOptional(before.RoundUpEstimations) != formatOptional(after.RoundUpEstimations) {
+		changes = append(changes, fmt.Sprintf("~ params round up estimations: %s -> %s", formatOptional(before.RoundUpEstimations), formatOptional(after.RoundUpEstimations)))
+	}
+	if formatOptional(before.Markup) != formatOptional(after.Markup) {
+		changes = append(changes, fmt.Sprintf("~ params markup: %s -> %s", formatOptional(before.Markup), formatOptional(after.Markup)))
+	}
+	if formatOptional(before.Discount) != formatOptional(after.Discount) {
+		changes = append(changes, fmt.Sprintf("~ params discount: %s -> %s", formatOptional(before.Discount), formatOptional(after.Discount)))
+	}
+
+	return changes
+}
+
+// describeChanges lists the differences between two versions of an estimation
+func describeChanges(before *model.Estimation, after *model.Estimation) []string {
+	var changes []string
+
+	if before.Label != after.Label {
+		changes = append(changes, fmt.Sprintf("~ label: %q -> %q", before.Label, after.Label))
+	}
+	if before.Description != after.Description {
+		changes = append(changes, fmt.Sprintf("~ description: %q -> %q", before.Description, after.Description))
+	}
+	if before.Owner != after.Owner {
+		changes = append(changes, fmt.Sprintf("~ owner: %q -> %q", before.Owner, after.Owner))
+	}
+	if before.TeamSize != after.TeamSize {
+		changes = append(changes, fmt.Sprintf("~ team size: %d -> %d", before.TeamSize, after.TeamSize))
+	}
+	if before.Locked != after.Locked {
+		changes = append(changes, fmt.Sprintf("~ locked: %v -> %v", before.Locked, after.Locked))
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		changes = append(changes, fmt.Sprintf("~ tags: %v -> %v", before.Tags, after.Tags))
+	}
+	if formatApproval(before) != formatApproval(after) {
+		changes = append(changes, fmt.Sprintf("~ approval: %s -> %s", formatApproval(before), formatApproval(after)))
+	}
+	changes = append(changes, describeParamsChanges(before.Params, after.Params)...)
+
+	for _, task := range before.GetOrderedTasks() {
+		if _, ok := after.Tasks[task.ID]; !ok {
+			changes = append(changes, fmt.Sprintf("- task [%s] %s", task.ID, task.Label))
+		}
+	}
+
+	for _, task := range after.GetOrderedTasks() {
+		previous, ok := before.Tasks[task.ID]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("+ task [%s] %s (%s) O: %.2f, L: %.2f, P: %.2f",
+				task.ID, task.Label, task.Category,
+				task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic))
+			continue
+		}
+
+		if previous.Label != task.Label {
+			changes = append(changes, fmt.Sprintf("~ task [%s] label: %q -> %q", task.ID, previous.Label, task.Label))
+		}
+		if previous.Description != task.Description {
+			changes = append(changes, fmt.Sprintf("~ task [%s] description: %q -> %q", task.ID, previous.Description, task.Description))
+		}
+		if previous.Category != task.Category {
+			changes = append(changes, fmt.Sprintf("~ task [%s] category: %s -> %s", task.ID, previous.Category, task.Category))
+		}
+		if previous.Assignee != task.Assignee {
+			changes = append(changes, fmt.Sprintf("~ task [%s] assignee: %q -> %q", task.ID, previous.Assignee, task.Assignee))
+		}
+		if formatMaxEstimate(previous.MaxEstimate) != formatMaxEstimate(task.MaxEstimate) {
+			changes = append(changes, fmt.Sprintf("~ task [%s] max estimate: %s -> %s", task.ID, formatMaxEstimate(previous.MaxEstimate), formatMaxEstimate(task.MaxEstimate)))
+		}
+		if previous.Confidence != task.Confidence {
+			changes = append(changes, fmt.Sprintf("~ task [%s] confidence: %q -> %q", task.ID, previous.Confidence, task.Confidence))
+		}
+		if !slices.Equal(previous.Tags, task.Tags) {
+			changes = append(changes, fmt.Sprintf("~ task [%s] tags: %q -> %q", task.ID, previous.Tags, task.Tags))
+		}
+		if previous.FixedCost != task.FixedCost {
+			changes = append(changes, fmt.Sprintf("~ task [%s] fixed cost: %g -> %g", task.ID, previous.FixedCost, task.FixedCost))
+		}
+		if previous.Estimations != task.Estimations {
+			changes = append(changes, fmt.Sprintf("~ task [%s] estimations: O: %.2f, L: %.2f, P: %.2f -> O: %.2f, L: %.2f, P: %.2f",
+				task.ID,
+				previous.Estimations.Optimistic, previous.Estimations.Likely, previous.Estimations.Pessimistic,
+				task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic))
+		}
+	}
+
+	// Only report reordering of the tasks present in both versions
+	var beforeOrder, afterOrder []model.TaskID
+	for _, id := range before.Ordering {
+		if _, ok := after.Tasks[id]; ok {
+			beforeOrder = append(beforeOrder, id)
+		}
+	}
+	for _, id := range after.Ordering {
+		if _, ok := before.Tasks[id]; ok {
+			afterOrder = append(afterOrder, id)
+		}
+	}
+	if !slices.Equal(beforeOrder, afterOrder) {
+		changes = append(changes, "~ task ordering changed")
+	}
+
+	return changes
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+func TestDescribeChanges(t *testing.T) {
+	markup := 10.0
+	approvedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		modify func(estimation *model.Estimation)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(estimation *model.Estimation) {},
+		},
+		{
+			name: "approval",
+			modify: func(estimation *model.Estimation) {
+				estimation.ApprovedBy = "alice"
+				estimation.ApprovedAt = &approvedAt
+			},
+			want: []string{`~ approval: none -> by "alice" at 2026-01-02T03:04:05Z`},
+		},
+		{
+			name: "params",
+			modify: func(estimation *model.Estimation) {
+				estimation.Params = &model.EstimationParams{Currency: "EUR", Markup: &markup}
+				estimation.Params.AddCategory("design", "Design", 500)
+			},
+			want: []string{
+				`+ params category design "Design" (500)`,
+				`~ params currency: "" -> "EUR"`,
+				`~ params markup: none -> 10`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := model.NewEstimation("Project")
+			after := before.Clone()
+			tt.modify(after)
+
+			if got := describeChanges(before, after); !slices.Equal(got, tt.want) {
+				t.Errorf("describeChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
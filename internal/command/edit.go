@@ -9,22 +9,43 @@ import (
 
 // editCmd represents the edit command
 var editCmd = &cobra.Command{
-	Use:   "edit <file>",
+	Use:   "edit [file]",
 	Short: "Edit an estimation interactively",
-	Long:  `Open an interactive terminal UI to edit an estimation file.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Open an interactive terminal UI to edit an estimation file.
+
+Without a file and without a default estimation, or with --pick, a picker lists
+the estimations of the current directory to choose the one to edit, or to create
+a new one.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		file := args[0]
+		pick, _ := cmd.Flags().GetBool("pick")
+		if pick && len(args) > 0 {
+			return fmt.Errorf("--pick can't be used with a file")
+		}
 
 		s := getStore()
 
+		var file string
+		if !pick && (len(args) > 0 || defaultEstimationFile() != "") {
+			file = withDefaultFile(args)[0]
+		} else {
+			picked, err := ui.NewPicker(s, ".").Run()
+			if err != nil {
+				return fmt.Errorf("failed to run picker: %w", err)
+			}
+			if picked == "" {
+				return nil
+			}
+			file = picked
+		}
+
 		// Load or create estimation
 		estimation, created, err := s.LoadOrCreateEstimation(file, file)
 		if err != nil {
 			return fmt.Errorf("failed to load estimation: %w", err)
 		}
 		if created {
-			fmt.Printf("Created new estimation file: %s\n", file)
+			infof("Created new estimation file: %s\n", file)
 		}
 
 		// Load config
@@ -32,6 +53,7 @@ var editCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		config = config.WithParams(estimation.Params)
 
 		// Create and run UI
 		app := ui.NewApp(s, config, estimation, file)
@@ -45,4 +67,6 @@ var editCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().Bool("pick", false, "Choose the estimation among the ones of the current directory")
 }
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+// Stable error codes reported with --json-errors
+const (
+	errCodeFileNotFound     = "file-not-found"
+	errCodeTaskNotFound     = "task-not-found"
+	errCodeValidationFailed = "validation-failed"
+	errCodeLocked           = "estimation-locked"
+	errCodeOutsideRoot      = "path-outside-root"
+	errCodeFileTooLarge     = "file-too-large"
+	errCodeNotEstimation    = "not-an-estimation"
+	errCodeNoCategories     = "no-categories"
+	errCodeBaselineExceeded = "baseline-exceeded"
+	errCodeUnknown          = "error"
+)
+
+// codedError is an error carrying a stable error code
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withCode attaches the given error code to the error
+func withCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// taskNotFoundError returns the error reported when a task doesn't exist
+func taskNotFoundError(id model.TaskID) error {
+	return withCode(errCodeTaskNotFound, fmt.Errorf("task with ID '%s' not found", id))
+}
+
+// errorCode returns the stable code of the given error
+func errorCode(err error) string {
+	var coded *codedError
+	switch {
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, fs.ErrNotExist):
+		return errCodeFileNotFound
+	case errors.Is(err, model.ErrEstimationLocked):
+		return errCodeLocked
+	case errors.Is(err, store.ErrPathOutsideRoot):
+		return errCodeOutsideRoot
+	case errors.Is(err, store.ErrFileTooLarge):
+		return errCodeFileTooLarge
+	case errors.Is(err, store.ErrNotEstimation):
+		return errCodeNotEstimation
+	case errors.Is(err, model.ErrNoCategories):
+		return errCodeNoCategories
+	default:
+		return errCodeUnknown
+	}
+}
+
+// writeJSONError writes the error as a {"error": "...", "code": "..."} JSON object
+func writeJSONError(w io.Writer, err error) {
+	data, marshalErr := json.Marshal(struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}{
+		Error: err.Error(),
+		Code:  errorCode(err),
+	})
+	if marshalErr != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
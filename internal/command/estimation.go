@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/bornholm/guesstimate/internal/format"
+	"github.com/bornholm/guesstimate/internal/i18n"
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/bornholm/guesstimate/internal/store"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -17,18 +20,25 @@ import (
 var newCmd = &cobra.Command{
 	Use:   "new <name>",
 	Short: "Create a new estimation",
-	Long:  `Create a new estimation file with the given name.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Create a new estimation file with the given name.
+
+Use --from to scaffold a fully-populated estimation from a JSON or YAML spec
+listing tasks and estimation-specific categories.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		output, _ := cmd.Flags().GetString("output")
 		description, _ := cmd.Flags().GetString("description")
+		owner, _ := cmd.Flags().GetString("owner")
+		teamSize, _ := cmd.Flags().GetInt("team-size")
+
+		if teamSize < 0 {
+			return fmt.Errorf("team size must be >= 0")
+		}
 
 		// Generate output filename if not provided
 		if output == "" {
-			// Sanitize name for filename
-			safeName := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
-			output = safeName + ".estimation.yml"
+			output = store.EstimationFileName(name)
 		}
 
 		s := getStore()
@@ -42,65 +52,149 @@ var newCmd = &cobra.Command{
 		}
 
 		// Create estimation
-		estimation := model.NewEstimation(name)
-		estimation.Description = description
+		var estimation *model.Estimation
+		from, _ := cmd.Flags().GetString("from")
+		if from != "" {
+			spec, err := loadEstimationSpec(from)
+			if err != nil {
+				return fmt.Errorf("failed to load spec: %w", err)
+			}
+
+			config, err := s.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			estimation, err = model.NewEstimationFromSpec(name, spec, config)
+			if err != nil {
+				return err
+			}
+		} else {
+			estimation = model.NewEstimation(name)
+		}
+
+		if description != "" {
+			estimation.Description = description
+		}
+		if owner != "" {
+			estimation.Owner = owner
+		}
+		if teamSize > 0 {
+			estimation.TeamSize = teamSize
+		}
 
-		if err := s.SaveEstimation(output, estimation); err != nil {
-			return fmt.Errorf("failed to create estimation: %w", err)
+		if err := saveEstimation(s, output, nil, estimation); err != nil {
+			return err
 		}
 
-		fmt.Printf("Created estimation '%s' at %s\n", name, output)
+		if len(estimation.Tasks) > 0 {
+			infof("Created estimation '%s' at %s with %d tasks\n", name, output, len(estimation.Tasks))
+		} else {
+			infof("Created estimation '%s' at %s\n", name, output)
+		}
 		return nil
 	},
 }
 
 // viewCmd represents the view command
 var viewCmd = &cobra.Command{
-	Use:   "view <file>",
+	Use:   "view [file]...",
 	Short: "View an estimation",
-	Long:  `View an estimation in various formats (markdown, json, yaml).`,
-	Args:  cobra.ExactArgs(1),
+	Long: `View an estimation in various formats (markdown, json, yaml).
+
+Given several files, a single read-only report combining all their tasks is
+produced, without writing any merged file. The time unit, currency, rounding,
+markup and discount of the first file apply to the whole report. Categories
+defined with different rates by several files, and the files using other
+parameters, are reported as warnings in the report and on stderr.`,
+	Args: optionalFileArgs(cobra.MinimumNArgs(1)),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		file := args[0]
+		args = withDefaultFile(args)
 		formatType, _ := cmd.Flags().GetString("format")
 		output, _ := cmd.Flags().GetString("output")
 
 		s := getStore()
 
-		// Load estimation
-		estimation, err := s.LoadEstimation(file)
-		if err != nil {
-			return fmt.Errorf("failed to load estimation: %w", err)
-		}
-
 		// Load config
 		config, err := s.LoadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
+		// Load estimation, combining the files if several are given
+		var estimation *model.Estimation
+		var warnings []string
+		if len(args) > 1 {
+			estimation, config, warnings, err = loadCombinedEstimation(s, args, config)
+			if err != nil {
+				return err
+			}
+			for _, warning := range warnings {
+				infof("Warning: %s\n", warning)
+			}
+		} else {
+			estimation, err = s.LoadEstimation(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load estimation: %w", err)
+			}
+			config = config.WithParams(estimation.Params)
+		}
+		if err := applyLanguage(cmd, config); err != nil {
+			return err
+		}
+		if err := applyCostModel(cmd, config); err != nil {
+			return err
+		}
+		applyNoCost(cmd, config)
+
+		generatedAt := time.Now()
+		if noGeneratedAt, _ := cmd.Flags().GetBool("no-generated-at"); noGeneratedAt {
+			generatedAt = time.Time{}
+		}
+
+		// Stream large JSON outputs directly to the file
+		if formatType == "json" && output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			defer f.Close()
+
+			if err := format.NewJSONFormatter(config).WithGeneratedAt(generatedAt).WithWarnings(warnings).Stream(estimation, f); err != nil {
+				return fmt.Errorf("failed to format estimation as JSON: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			infof("Output written to %s\n", output)
+			return nil
+		}
+
 		var result string
+		noTasks, _ := cmd.Flags().GetBool("no-tasks")
+		allCategories, _ := cmd.Flags().GetBool("all-categories")
 
 		switch formatType {
 		case "markdown", "md":
-			formatter := format.NewMarkdownFormatter(config)
+			formatter := format.NewMarkdownFormatter(config).WithTasks(!noTasks).WithAllCategories(allCategories).WithWarnings(warnings)
 			result = formatter.Format(estimation)
 		case "json":
-			formatter := format.NewJSONFormatter(config)
+			formatter := format.NewJSONFormatter(config).WithGeneratedAt(generatedAt).WithWarnings(warnings)
 			var err error
 			result, err = formatter.Format(estimation)
 			if err != nil {
 				return fmt.Errorf("failed to format estimation as JSON: %w", err)
 			}
 		case "yaml", "yml":
-			formatter := format.NewYAMLFormatter(config)
+			formatter := format.NewYAMLFormatter(config).WithGeneratedAt(generatedAt).WithWarnings(warnings)
 			var err error
 			result, err = formatter.Format(estimation)
 			if err != nil {
 				return fmt.Errorf("failed to format estimation as YAML: %w", err)
 			}
 		default:
-			formatter := format.NewMarkdownFormatter(config)
+			formatter := format.NewMarkdownFormatter(config).WithTasks(!noTasks).WithAllCategories(allCategories).WithWarnings(warnings)
 			result = formatter.Format(estimation)
 		}
 
@@ -109,7 +203,7 @@ var viewCmd = &cobra.Command{
 			if err := os.WriteFile(output, []byte(result), 0644); err != nil {
 				return fmt.Errorf("failed to write output: %w", err)
 			}
-			fmt.Printf("Output written to %s\n", output)
+			infof("Output written to %s\n", output)
 		} else {
 			fmt.Print(result)
 		}
@@ -120,12 +214,34 @@ var viewCmd = &cobra.Command{
 
 // summaryCmd represents the summary command
 var summaryCmd = &cobra.Command{
-	Use:   "summary <file>",
+	Use:   "summary [file]",
 	Short: "Show estimation summary",
-	Long:  `Show a quick summary of the estimation with confidence intervals.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Show a quick summary of the estimation with confidence intervals.
+
+The cost estimation is based on the 99.7% confidence interval by default, which
+can be changed with --cost-confidence or the costConfidence configuration.
+
+Estimations in story points have no cost: set the velocity configuration, in
+story points per man-day, and use --as-time to convert them into man-days.
+
+Use --explain to show how the mean and standard deviation of each task and of the
+project are computed, with the formulas and their values.
+
+Use --calendar to convert the estimation into calendar weeks, according to the
+workingDaysPerWeek and hoursPerDay configuration and the team size.
+
+Use --baseline to compare the project mean and 99.7% maximum cost with the ones of
+a baseline estimation. The command fails when either grew beyond the threshold, in
+percent, given by --baseline-threshold or the baselineThreshold configuration
+(default: 10).
+
+Use --scenario to report the totals of a scenario applied to every task, without
+modifying the file: optimistic (likely estimates as optimistic), expected
+(estimates unchanged), pessimistic (likely estimates as pessimistic) or
+stress×<factor> (every estimate scaled by the factor, e.g. stress×1.5 or stressx2).`,
+	Args: optionalFileArgs(cobra.ExactArgs(1)),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		file := args[0]
+		file := withDefaultFile(args)[0]
 
 		s := getStore()
 
@@ -136,45 +252,284 @@ var summaryCmd = &cobra.Command{
 		}
 
 		// Load config
-		config, err := s.LoadConfig()
+		baseConfig, err := s.LoadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		// The baseline is costed with the same model, if costed at all
+		if err := applyCostModel(cmd, baseConfig); err != nil {
+			return err
+		}
+		applyNoCost(cmd, baseConfig)
+		config := baseConfig.WithParams(estimation.Params)
+		if err := applyLanguage(cmd, config); err != nil {
+			return err
+		}
+		tr := i18n.For(config.Language)
+
+		// Apply the scenario to a copy of the estimation, the file being left untouched
+		var scenarioLine string
+		if scenario, _ := cmd.Flags().GetString("scenario"); scenario != "" {
+			shifted, err := stats.ApplyScenario(estimation, scenario)
+			if err != nil {
+				return err
+			}
+			scenarioLine = tr.Sprintf("Scenario: %s (mean %.2f %s, estimated %.2f %s)", scenario,
+				stats.CalculateProjectEstimationFor(shifted, config).WeightedMean, config.TimeUnit.Acronym,
+				stats.CalculateProjectEstimationFor(estimation, config).WeightedMean, config.TimeUnit.Acronym)
+			estimation = shifted
+		}
+
+		// Compare with the baseline before any conversion, reported last
+		var comparison *baselineComparison
+		if baselineFile, _ := cmd.Flags().GetString("baseline"); baselineFile != "" {
+			if comparison, err = compareToBaseline(s, baselineFile, baseConfig, estimation); err != nil {
+				return err
+			}
+		}
+		threshold := config.GetBaselineThreshold()
+		if cmd.Flags().Changed("baseline-threshold") {
+			threshold, _ = cmd.Flags().GetFloat64("baseline-threshold")
+		}
+		printBaseline := func() error {
+			if comparison == nil {
+				return nil
+			}
+			fmt.Println()
+			return comparison.print(tr, threshold)
+		}
 
 		// Calculate estimation
-		projectEst := stats.CalculateProjectEstimation(estimation)
-		costs := stats.CalculateMinMaxCosts(estimation, config, stats.Confidence997)
+		projectEst := stats.CalculateProjectEstimationFor(estimation, config)
+		costConfidence := stats.CostConfidenceLevel(config)
+		if value, _ := cmd.Flags().GetString("cost-confidence"); value != "" {
+			costConfidence, err = stats.ParseConfidenceLevel(config, value)
+			if err != nil {
+				return err
+			}
+		}
 		distribution := stats.CalculateCategoryDistribution(estimation, config)
 
+		var tagSubtotals []stats.TagSubtotal
+		switch groupBy, _ := cmd.Flags().GetString("group-by"); groupBy {
+		case "":
+		case "tag":
+			tagSubtotals = stats.CalculateTagSubtotals(estimation, config)
+		default:
+			return fmt.Errorf("unknown grouping '%s', expected tag", groupBy)
+		}
+
+		if verify, _ := cmd.Flags().GetBool("verify"); verify {
+			for _, issue := range stats.CheckCategoryDistribution(projectEst, distribution) {
+				infof("Warning: %s\n", issue)
+			}
+		}
+
+		// The formulas are explained in the estimation unit, before any conversion
+		var explanation []string
+		if explain, _ := cmd.Flags().GetBool("explain"); explain && !projectEst.IsEmpty() {
+			explanation = explainEstimation(estimation, config, projectEst, tr)
+		}
+
+		// Story points have no cost until converted into time
+		storyPoints := config.IsStoryPointTimeUnit()
+		if asTime, _ := cmd.Flags().GetBool("as-time"); asTime {
+			if !storyPoints {
+				return fmt.Errorf("--as-time requires a story point time unit")
+			}
+			if config.Velocity <= 0 {
+				return fmt.Errorf("--as-time requires a velocity in the configuration")
+			}
+
+			if explanation != nil {
+				explanation = append(explanation,
+					fmt.Sprintf("    E = %.2f/%s = %.2f", projectEst.WeightedMean, formatOperand(config.Velocity), projectEst.WeightedMean/config.Velocity),
+					fmt.Sprintf("    SD = %.2f/%s = %.2f", projectEst.StandardDeviation, formatOperand(config.Velocity), projectEst.StandardDeviation/config.Velocity))
+			}
+
+			projectEst = projectEst.Scale(1 / config.Velocity)
+			for i := range distribution {
+				distribution[i] = distribution[i].Scale(1 / config.Velocity)
+			}
+			for i := range tagSubtotals {
+				tagSubtotals[i] = tagSubtotals[i].Scale(1 / config.Velocity)
+			}
+			config = config.Clone()
+			config.TimeUnit = model.ManDay
+			storyPoints = false
+		}
+
 		// Print summary
-		fmt.Printf("Project: %s\n", estimation.Label)
-		fmt.Printf("Tasks: %d\n", len(estimation.Tasks))
+		fmt.Println(tr.Sprintf("Project: %s", estimation.Label))
+		fmt.Println(tr.Sprintf("Tasks: %d", len(estimation.Tasks)))
+		if scenarioLine != "" {
+			fmt.Println(scenarioLine)
+		}
 		fmt.Println()
-		fmt.Println("Time Estimation:")
-		fmt.Printf("  99.7%% confidence: %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation*3, config.TimeUnit.Acronym)
-		fmt.Printf("  90%% confidence:   %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation*1.645, config.TimeUnit.Acronym)
-		fmt.Printf("  68%% confidence:   %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation, config.TimeUnit.Acronym)
+
+		if projectEst.IsEmpty() {
+			fmt.Println(tr.T("No estimates yet"))
+			return printBaseline()
+		}
+
+		fmt.Println(tr.T("Time Estimation:"))
+		if correlation := config.GetCorrelationCoefficient(); correlation > 0 {
+			fmt.Printf("  %s\n", tr.Sprintf("(task correlation coefficient: %.2f)", correlation))
+		}
+		for _, cl := range stats.GetConfidenceLevels(config) {
+			fmt.Printf("  %-17s %.2f ± %.2f %s\n", tr.Sprintf("%s confidence:", cl.Name), projectEst.WeightedMean, projectEst.StandardDeviation*cl.Multiplier, config.TimeUnit.Acronym)
+		}
 		fmt.Println()
 
-		// Category distribution
+		// Three-point math
+		if explanation != nil {
+			fmt.Println(tr.T("Calculation:"))
+			for _, line := range explanation {
+				fmt.Println(line)
+			}
+			fmt.Println()
+		}
+
+		// Calendar duration, story points having no duration until converted into time
+		calendar, _ := cmd.Flags().GetBool("calendar")
+		if calendar && storyPoints {
+			if config.Velocity > 0 {
+				fmt.Println(tr.T("Calendar Duration: unavailable for story points, use --as-time"))
+			} else {
+				fmt.Println(tr.T("Calendar Duration: unavailable for story points, set a velocity and use --as-time"))
+			}
+			fmt.Println()
+		} else if calendar {
+			teamSize := estimation.GetTeamSize()
+			if teamSize > 1 {
+				fmt.Println(tr.Sprintf("Calendar Duration (%g working days per week, team of %d):", config.GetWorkingDaysPerWeek(), teamSize))
+			} else {
+				fmt.Println(tr.Sprintf("Calendar Duration (%g working days per week):", config.GetWorkingDaysPerWeek()))
+			}
+			for _, cl := range stats.GetConfidenceLevels(config) {
+				fmt.Printf("  %-17s %.2f ± %.2f %s\n", tr.Sprintf("%s confidence:", cl.Name),
+					stats.CalendarWeeks(config, projectEst.WeightedMean, teamSize),
+					stats.CalendarWeeks(config, projectEst.StandardDeviation*cl.Multiplier, teamSize),
+					tr.T("weeks"))
+			}
+			fmt.Println()
+		}
+
+		// Category distribution, unused categories being hidden unless asked for
+		allCategories, _ := cmd.Flags().GetBool("all-categories")
 		if len(distribution) > 0 {
-			fmt.Println("Category Repartition:")
-			for _, dist := range distribution {
-				if dist.Percentage > 0 {
+			fmt.Println(tr.T("Category Repartition:"))
+			for _, dist := range stats.SortByShare(distribution) {
+				if dist.Percentage > 0 || allCategories {
 					fmt.Printf("  %s: %.1f%% (%.2f %s)\n", dist.CategoryLabel, dist.Percentage, dist.Time, config.TimeUnit.Acronym)
 				}
 			}
 			fmt.Println()
 		}
 
-		fmt.Println("Cost Estimation (99.7% confidence):")
-		fmt.Printf("  Maximum: %.2f %s (%.2f %s)\n", costs.Max.TotalCost, config.Currency, costs.Max.TotalTime, config.TimeUnit.Acronym)
-		fmt.Printf("  Minimum: %.2f %s (%.2f %s)\n", costs.Min.TotalCost, config.Currency, costs.Min.TotalTime, config.TimeUnit.Acronym)
+		// Tag subtotals, overlapping as tasks may have several tags
+		if len(tagSubtotals) > 0 {
+			fmt.Println(tr.T("Tag Subtotals (overlapping, a task counts in each of its tags):"))
+			for _, subtotal := range tagSubtotals {
+				line := fmt.Sprintf("  %s: %.1f%% (%.2f %s", subtotal.Tag, subtotal.Percentage, subtotal.WeightedMean, config.TimeUnit.Acronym)
+				if config.HasCosts() {
+					line += fmt.Sprintf(", %.2f %s", subtotal.Cost+subtotal.FixedCost, config.Currency)
+				}
+				fmt.Println(line + ", " + tr.Sprintf("%d tasks", subtotal.Tasks) + ")")
+			}
+			fmt.Println()
+		}
 
-		return nil
+		if !config.GetShowCost() {
+			return printBaseline()
+		}
+
+		if storyPoints {
+			if config.Velocity > 0 {
+				fmt.Println(tr.T("Cost Estimation: unavailable for story points, use --as-time"))
+			} else {
+				fmt.Println(tr.T("Cost Estimation: unavailable for story points, set a velocity and use --as-time"))
+			}
+			return printBaseline()
+		}
+
+		costs := stats.CalculateMinMaxCostsFrom(projectEst, distribution, stats.CalculateFixedCost(estimation), config, costConfidence)
+
+		fmt.Println(tr.Sprintf("Cost Estimation (%s confidence):", costConfidence.Name))
+		fmt.Printf("  %s %.2f %s (%.2f %s)\n", tr.T("Maximum:"), costs.Max.TotalCost, config.Currency, costs.Max.TotalTime, config.TimeUnit.Acronym)
+		fmt.Printf("  %s %.2f %s (%.2f %s)\n", tr.T("Minimum:"), costs.Min.TotalCost, config.Currency, costs.Min.TotalTime, config.TimeUnit.Acronym)
+		if costs.Max.FixedCost > 0 {
+			fmt.Printf("  %s\n", tr.Sprintf("Including %.2f %s of fixed costs", costs.Max.FixedCost, config.Currency))
+		}
+
+		if config.HasCostAdjustments() {
+			printCostAdjustments(tr, config, costs)
+		}
+
+		return printBaseline()
 	},
 }
 
+// printCostAdjustments prints the markup and the discount applied to the min and max costs,
+// from the subtotal to the total
+func printCostAdjustments(tr *i18n.Translator, config *model.Config, costs stats.MinMaxCost) {
+	maxCost := stats.AdjustCost(costs.Max.TotalCost, config)
+	minCost := stats.AdjustCost(costs.Min.TotalCost, config)
+
+	fmt.Println()
+	fmt.Println(tr.T("Cost Adjustments:"))
+	fmt.Printf("  %-16s %18s %18s\n", "", tr.T("Maximum"), tr.T("Minimum"))
+	printLine := func(label string, maxAmount, minAmount float64) {
+		fmt.Printf("  %-16s %18s %18s\n", label,
+			fmt.Sprintf("%.2f %s", maxAmount, config.Currency), fmt.Sprintf("%.2f %s", minAmount, config.Currency))
+	}
+	printLine(tr.T("Subtotal"), maxCost.Subtotal, minCost.Subtotal)
+	if config.Markup != 0 {
+		printLine(tr.Sprintf("Markup +%g%%", config.Markup), maxCost.Markup, minCost.Markup)
+	}
+	if config.Discount != 0 {
+		printLine(tr.Sprintf("Discount -%g%%", config.Discount), -maxCost.Discount, -minCost.Discount)
+	}
+	printLine(tr.T("Total"), maxCost.Total, minCost.Total)
+}
+
+// applyLanguage overrides the report language of the configuration with the --lang flag, if set
+func applyLanguage(cmd *cobra.Command, config *model.Config) error {
+	lang, _ := cmd.Flags().GetString("lang")
+	if lang == "" {
+		return nil
+	}
+
+	lang, err := i18n.Normalize(lang)
+	if err != nil {
+		return err
+	}
+	config.Language = lang
+
+	return nil
+}
+
+// applyCostModel overrides the cost model of the configuration with the --cost-model flag, if set
+func applyCostModel(cmd *cobra.Command, config *model.Config) error {
+	costModel, _ := cmd.Flags().GetString("cost-model")
+	switch costModel {
+	case "":
+	case model.CostModelProportional, model.CostModelIndependent:
+		config.CostModel = costModel
+	default:
+		return fmt.Errorf("unknown cost model '%s', expected %s or %s", costModel, model.CostModelProportional, model.CostModelIndependent)
+	}
+	return nil
+}
+
+// applyNoCost disables the costs of the configuration with the --no-cost flag, if set
+func applyNoCost(cmd *cobra.Command, config *model.Config) {
+	if noCost, _ := cmd.Flags().GetBool("no-cost"); noCost {
+		showCost := false
+		config.ShowCost = &showCost
+	}
+}
+
 // EstimationListItem represents an item in the estimation list output
 type EstimationListItem struct {
 	File  string `json:"file" yaml:"file"`
@@ -186,14 +541,29 @@ type EstimationListItem struct {
 var listCmd = &cobra.Command{
 	Use:   "list [directory]",
 	Short: "List estimation files",
-	Long:  `List all estimation files in the specified directory (default: current directory).`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `List all estimation files in the specified directory (default: current directory).
+
+Use --since and --until to only list the estimations last updated in a period,
+given as an RFC3339 date or a duration before now (e.g. 7d, 2w or 12h). Files
+that fail to load are then left out of the list with a warning.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir := "."
 		if len(args) > 0 {
 			dir = args[0]
 		}
 
+		now := time.Now()
+		since, err := parseListTime(cmd, "since", now)
+		if err != nil {
+			return err
+		}
+		until, err := parseListTime(cmd, "until", now)
+		if err != nil {
+			return err
+		}
+		filtered := !since.IsZero() || !until.IsZero()
+
 		s := getStore()
 
 		files, err := s.ListEstimations(dir)
@@ -201,13 +571,8 @@ var listCmd = &cobra.Command{
 			return fmt.Errorf("failed to list estimations: %w", err)
 		}
 
-		if len(files) == 0 {
-			fmt.Println("No estimation files found.")
-			return nil
-		}
-
 		// Build list items
-		var items []EstimationListItem
+		items := make([]EstimationListItem, 0, len(files))
 		for _, file := range files {
 			// Try to load the estimation to get its label
 			filePath := file
@@ -216,6 +581,11 @@ var listCmd = &cobra.Command{
 			}
 			estimation, err := s.LoadEstimation(filePath)
 			if err != nil {
+				// Without its update date, the estimation can't be filtered
+				if filtered {
+					infof("Warning: %s excluded from the date filtering: %v\n", file, err)
+					continue
+				}
 				items = append(items, EstimationListItem{
 					File:  file,
 					Label: "(error loading)",
@@ -223,6 +593,9 @@ var listCmd = &cobra.Command{
 				})
 				continue
 			}
+			if (!since.IsZero() && estimation.UpdatedAt.Before(since)) || (!until.IsZero() && estimation.UpdatedAt.After(until)) {
+				continue
+			}
 			items = append(items, EstimationListItem{
 				File:  file,
 				Label: estimation.Label,
@@ -247,6 +620,10 @@ var listCmd = &cobra.Command{
 			}
 			fmt.Print(string(data))
 		default:
+			if len(files) == 0 {
+				infof("No estimation files found.\n")
+				return nil
+			}
 			fmt.Println("Estimation files:")
 			for _, item := range items {
 				fmt.Printf("  %s - %s (%d tasks)\n", item.File, item.Label, item.Tasks)
@@ -257,6 +634,46 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// parseListTime parses the date of a --since or --until flag, either an RFC3339 date or
+// a duration before now in days (7d), weeks (2w) or any Go duration unit (12h). The zero
+// time is returned if the flag is not set.
+func parseListTime(cmd *cobra.Command, flag string, now time.Time) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	var duration time.Duration
+	switch unit := value[len(value)-1]; unit {
+	case 'd', 'w':
+		count, err := strconv.ParseFloat(value[:len(value)-1], 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s '%s': expected an RFC3339 date or a duration like 7d", flag, value)
+		}
+		days := count
+		if unit == 'w' {
+			days *= 7
+		}
+		duration = time.Duration(days * float64(24*time.Hour))
+	default:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s '%s': expected an RFC3339 date or a duration like 7d", flag, value)
+		}
+		duration = d
+	}
+
+	if duration < 0 {
+		return time.Time{}, fmt.Errorf("invalid --%s '%s': the duration must be positive", flag, value)
+	}
+
+	return now.Add(-duration), nil
+}
+
 func init() {
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(viewCmd)
@@ -266,12 +683,37 @@ func init() {
 	// new command flags
 	newCmd.Flags().StringP("output", "o", "", "Output file path (default: <name>.estimation.yml)")
 	newCmd.Flags().StringP("description", "d", "", "Project description")
+	newCmd.Flags().String("owner", "", "Project owner")
+	newCmd.Flags().Int("team-size", 0, "Number of people working on the project")
 	newCmd.Flags().BoolP("force", "f", false, "Force overwrite existing file")
+	newCmd.Flags().String("from", "", "Create the estimation from a JSON or YAML spec file")
 
 	// view command flags
+	summaryCmd.Flags().String("cost-confidence", "", "Confidence level of the cost estimation, e.g. 90 (default: costConfidence or 99.7)")
+	summaryCmd.Flags().Bool("as-time", false, "Convert story points into man-days using the configured velocity")
+	summaryCmd.Flags().Bool("explain", false, "Show the three-point formulas of each task and of the project with their values")
+	summaryCmd.Flags().String("group-by", "", "Also report subtotals grouped by tag (tag)")
+	summaryCmd.Flags().Bool("verify", false, "Warn on stderr when the category times don't reconcile with the project mean")
+	summaryCmd.Flags().Bool("all-categories", false, "Show every configured category in the repartition, unused ones included")
+	summaryCmd.Flags().Bool("calendar", false, "Show the estimation as a calendar duration in weeks")
+	summaryCmd.Flags().String("scenario", "", "Report the totals of a scenario: optimistic, expected, pessimistic or stress×<factor>")
+	summaryCmd.Flags().String("baseline", "", "Baseline estimation file to compare the mean and 99.7% cost with")
+	summaryCmd.Flags().Float64("baseline-threshold", 0, "Growth over the baseline, in percent, failing the command (default: baselineThreshold or 10)")
+	summaryCmd.Flags().String("lang", "", "Language of the report labels, en or fr (default: language or en)")
+	summaryCmd.Flags().Bool("no-cost", false, "Report the time estimations only, without any cost (default: showCost)")
+	summaryCmd.Flags().String("cost-model", "", "How the min and max costs are derived, proportional or independent (default: costModel or proportional)")
+
 	viewCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, yaml)")
 	viewCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	viewCmd.Flags().Bool("no-generated-at", false, "Omit the generation time from the JSON and YAML outputs, making them reproducible")
+	viewCmd.Flags().Bool("all-categories", false, "List every configured category in the markdown report, unused ones included (always listed in JSON and YAML)")
+	viewCmd.Flags().Bool("no-tasks", false, "Omit the tasks table from the markdown report, e.g. for a one-page summary")
+	viewCmd.Flags().String("lang", "", "Language of the markdown report labels, en or fr (default: language or en)")
+	viewCmd.Flags().Bool("no-cost", false, "Report the time estimations only, without any cost (default: showCost)")
+	viewCmd.Flags().String("cost-model", "", "How the min and max costs are derived, proportional or independent (default: costModel or proportional)")
 
 	// list command flags
 	listCmd.Flags().StringP("format", "f", "text", "Output format (text, json, yaml)")
+	listCmd.Flags().String("since", "", "Only list the estimations updated since this RFC3339 date or duration before now (e.g. 7d)")
+	listCmd.Flags().String("until", "", "Only list the estimations updated until this RFC3339 date or duration before now (e.g. 7d)")
 }
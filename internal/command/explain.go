@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/i18n"
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+)
+
+// explainEstimation returns the three-point formulas of each task and of the project with
+// their values substituted. The results come from the task and project calculations, so
+// that the displayed math always matches the reported figures.
+func explainEstimation(estimation *model.Estimation, config *model.Config, projectEst stats.EstimationResult, tr *i18n.Translator) []string {
+	estimationModel := config.GetEstimationModel()
+	divisor := formatOperand(model.StandardDeviationDivisor(estimationModel))
+
+	var lines []string
+	var means, deviations []string
+	var sumSquares, sumDeviations float64
+
+	for _, task := range estimation.GetOrderedTasks() {
+		o, l, p := task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic
+		mean := task.WeightedMean()
+		sd := task.StandardDeviationWith(estimationModel)
+
+		lines = append(lines, fmt.Sprintf("  [%s] %s", task.ID, task.Label))
+
+		formula := fmt.Sprintf("(%s + 4×%s + %s)/6", formatOperand(o), formatOperand(l), formatOperand(p))
+		if task.MaxEstimate != nil {
+			formula = fmt.Sprintf("min(%s, %s)", formula, formatOperand(*task.MaxEstimate))
+			o = math.Min(o, *task.MaxEstimate)
+			p = math.Min(p, *task.MaxEstimate)
+		}
+		lines = append(lines, fmt.Sprintf("    E = %s = %.2f", formula, mean))
+
+		formula = fmt.Sprintf("(%s - %s)/%s", formatOperand(p), formatOperand(o), divisor)
+		if factor := config.GetTaskConfidenceFactor(task.Confidence); factor != 1 {
+			formula = fmt.Sprintf("%s × %s (%s)", formula, formatOperand(factor), task.Confidence)
+			sd *= factor
+		}
+		lines = append(lines, fmt.Sprintf("    SD = %s = %.2f", formula, sd))
+
+		means = append(means, fmt.Sprintf("%.2f", mean))
+		deviations = append(deviations, fmt.Sprintf("%.2f²", sd))
+		sumSquares += sd * sd
+		sumDeviations += sd
+	}
+
+	lines = append(lines, "  "+tr.T("Project"))
+	lines = append(lines, fmt.Sprintf("    E = %s = %.2f", strings.Join(means, " + "), projectEst.WeightedMean))
+
+	if correlation := config.GetCorrelationCoefficient(); correlation > 0 {
+		lines = append(lines, fmt.Sprintf("    SD = √((1 - ρ)×ΣSD² + ρ×(ΣSD)²) = √((1 - %s)×%.2f + %s×%.2f²) = %.2f",
+			formatOperand(correlation), sumSquares, formatOperand(correlation), sumDeviations, projectEst.StandardDeviation))
+	} else {
+		lines = append(lines, fmt.Sprintf("    SD = √(%s) = %.2f", strings.Join(deviations, " + "), projectEst.StandardDeviation))
+	}
+
+	return lines
+}
+
+// formatOperand formats a formula operand without trailing zeros
+func formatOperand(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/format"
+	"github.com/spf13/cobra"
+)
+
+// hashCmd represents the hash command
+var hashCmd = &cobra.Command{
+	Use:   "hash [file]",
+	Short: "Compute the content hash of an estimation",
+	Long: `Compute a short, reproducible digest of the estimation content that can be cited
+as a stable reference. Timestamps are ignored, so the hash only changes when the
+content of the estimation does.`,
+	Args: optionalFileArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := withDefaultFile(args)[0]
+		full, _ := cmd.Flags().GetBool("full")
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		hash, err := format.ContentHash(estimation)
+		if err != nil {
+			return fmt.Errorf("failed to compute content hash: %w", err)
+		}
+
+		if !full {
+			hash = hash[:format.ShortHashLength]
+		}
+
+		fmt.Println(hash)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hashCmd)
+
+	hashCmd.Flags().Bool("full", false, "Print the full SHA-256 hash instead of the short form")
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// checklistItemPattern matches "- [ ] Label" and "* [x] Label" checklist lines
+	checklistItemPattern = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]\]\s+(.+)$`)
+
+	// trailingEstimatesPattern matches a trailing "(O/L/P)" or "(L)" estimate annotation
+	trailingEstimatesPattern = regexp.MustCompile(`\s*\(\s*([\d.]*)\s*(?:/\s*([\d.]*)\s*/\s*([\d.]*)\s*)?\)\s*$`)
+)
+
+// taskImportCmd represents the task import command
+var taskImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks",
+	Long: `Import tasks into an estimation file from a Markdown checklist.
+
+Each "- [ ] Task label (O/L/P)" line becomes a task. The estimates are optional:
+"(L)" only sets the likely estimate and missing values are auto-filled. Lines
+without the checklist syntax are ignored. Nothing is imported if any task is
+invalid, e.g. with an optimistic estimate above the likely one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		markdownFile, _ := cmd.Flags().GetString("markdown")
+		category, _ := cmd.Flags().GetString("category")
+
+		items, ignored, err := parseMarkdownTasks(markdownFile)
+		if err != nil {
+			return fmt.Errorf("failed to read markdown file: %w", err)
+		}
+
+		// The estimates are checked as written before creating any file, SetEstimations
+		// reordering them
+		var failures []string
+		for _, item := range items {
+			if estimateErrors := checklistEstimateErrors(item.Task); len(estimateErrors) > 0 {
+				failures = append(failures, fmt.Sprintf("line %d: %s: %s", item.Line, item.Task.Label, strings.Join(estimateErrors, ", ")))
+			}
+		}
+		if len(failures) > 0 {
+			return invalidChecklistError(failures)
+		}
+
+		s := getStore()
+
+		// Load or create estimation
+		estimation, created, err := loadOrCreateEstimation(s, file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+		original := estimation.Clone()
+		if created {
+			original = nil
+			if !dryRun {
+				infof("Created new estimation file: %s\n", file)
+			}
+		}
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		if category == "" {
+			if category, err = config.GetDefaultCategoryID(); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range items {
+			task := model.NewTask(item.Task.Label, category)
+			task.SetEstimations(item.Task.Optimistic, item.Task.Likely, item.Task.Pessimistic, config.GetAutoEstimationMultiplier())
+
+			if taskErrors := task.Validate(); len(taskErrors) > 0 {
+				failures = append(failures, fmt.Sprintf("line %d: %s: %s", item.Line, item.Task.Label, strings.Join(taskErrors, ", ")))
+				continue
+			}
+
+			estimation.AddTask(task)
+		}
+
+		if len(failures) > 0 {
+			return invalidChecklistError(failures)
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		infof("%d task(s) imported, %d line(s) ignored\n", len(items), ignored)
+		return nil
+	},
+}
+
+// checklistItem is a task spec read from a Markdown checklist, along with its line number
+type checklistItem struct {
+	Line int
+	Task model.TaskSpec
+}
+
+// parseMarkdownTasks reads the checklist items of a Markdown file as task specs,
+// returning the number of non-blank lines that were ignored
+func parseMarkdownTasks(path string) ([]checklistItem, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var items []checklistItem
+	ignored := 0
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		task, ok := parseChecklistItem(line)
+		if !ok {
+			ignored++
+			continue
+		}
+		items = append(items, checklistItem{Line: lineNumber, Task: task})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, ignored, nil
+}
+
+// invalidChecklistError returns the validation error listing the invalid checklist items
+func invalidChecklistError(failures []string) error {
+	return withCode(errCodeValidationFailed, fmt.Errorf("invalid markdown checklist, %d task(s) failed:\n  %s", len(failures), strings.Join(failures, "\n  ")))
+}
+
+// checklistEstimateErrors reports the estimates of a checklist item given out of order,
+// the missing (0) ones being auto-filled afterwards
+func checklistEstimateErrors(spec model.TaskSpec) []string {
+	var errors []string
+
+	if spec.Optimistic > 0 && spec.Likely > 0 && spec.Likely < spec.Optimistic {
+		errors = append(errors, "likely estimate should be >= optimistic estimate")
+	}
+	if spec.Likely > 0 && spec.Pessimistic > 0 && spec.Pessimistic < spec.Likely {
+		errors = append(errors, "pessimistic estimate should be >= likely estimate")
+	}
+	if spec.Likely == 0 && spec.Optimistic > 0 && spec.Pessimistic > 0 && spec.Pessimistic < spec.Optimistic {
+		errors = append(errors, "pessimistic estimate should be >= optimistic estimate")
+	}
+
+	return errors
+}
+
+// parseChecklistItem parses a "- [ ] Task label (O/L/P)" line
+func parseChecklistItem(line string) (model.TaskSpec, bool) {
+	match := checklistItemPattern.FindStringSubmatch(line)
+	if match == nil {
+		return model.TaskSpec{}, false
+	}
+
+	label := match[1]
+	var task model.TaskSpec
+
+	if estimates := trailingEstimatesPattern.FindStringSubmatch(label); estimates != nil {
+		values := make([]float64, 3)
+		valid := true
+		for i, value := range estimates[1:] {
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = parsed
+		}
+
+		if valid {
+			label = strings.TrimSuffix(label, estimates[0])
+			if !strings.Contains(estimates[0], "/") {
+				// A single value is the likely estimate
+				task.Likely = values[0]
+			} else {
+				task.Optimistic, task.Likely, task.Pessimistic = values[0], values[1], values[2]
+			}
+		}
+	}
+
+	task.Label = strings.TrimSpace(label)
+	if task.Label == "" {
+		return model.TaskSpec{}, false
+	}
+
+	return task, true
+}
+
+func init() {
+	taskCmd.AddCommand(taskImportCmd)
+
+	taskImportCmd.Flags().String("markdown", "", "Markdown file containing a \"- [ ] Task label (O/L/P)\" checklist")
+	taskImportCmd.Flags().String("category", "", "Category of the imported tasks (default: first category in config)")
+	taskImportCmd.Flags().Bool("force", false, "Allow modifying a locked estimation")
+	_ = taskImportCmd.MarkFlagRequired("markdown")
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// lockCmd represents the lock command
+var lockCmd = &cobra.Command{
+	Use:   "lock <file>",
+	Short: "Lock an estimation",
+	Long: `Lock a signed-off estimation so that its tasks can no longer be added, updated,
+removed or moved, unless --force is used or the estimation is unlocked.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		approvedBy, _ := cmd.Flags().GetString("by")
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		if estimation.Locked {
+			return fmt.Errorf("estimation '%s' is already locked", file)
+		}
+
+		original := estimation.Clone()
+		estimation.Lock(approvedBy)
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		if approvedBy != "" {
+			infof("Estimation %s locked, approved by %s\n", file, approvedBy)
+		} else {
+			infof("Estimation %s locked\n", file)
+		}
+		return nil
+	},
+}
+
+// unlockCmd represents the unlock command
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <file>",
+	Short: "Unlock an estimation",
+	Long:  `Unlock an estimation, allowing its tasks to be modified again and clearing its approval.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		if !estimation.Locked {
+			return fmt.Errorf("estimation '%s' is not locked", file)
+		}
+
+		original := estimation.Clone()
+		estimation.Unlock()
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		infof("Estimation %s unlocked\n", file)
+		return nil
+	},
+}
+
+// checkUnlocked refuses modifications of a locked estimation unless --force is set
+func checkUnlocked(cmd *cobra.Command, estimation *model.Estimation) error {
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		return nil
+	}
+
+	if err := estimation.CheckUnlocked(); err != nil {
+		return fmt.Errorf("%w (or use --force)", err)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+
+	lockCmd.Flags().String("by", "", "Name of the person approving the estimation")
+}
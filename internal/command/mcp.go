@@ -11,10 +11,6 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var (
-	mcpRootDir string
-)
-
 // mcpCmd represents the mcp command
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
@@ -25,22 +21,27 @@ var mcpCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(mcpCmd)
 	mcpCmd.AddCommand(mcpServerCmd)
-	mcpServerCmd.Flags().StringVar(&mcpRootDir, "root", "", "Root directory for the MCP server (default: current working directory)")
 }
 
 // mcpServerCmd represents the mcp server command
 var mcpServerCmd = &cobra.Command{
 	Use:   "server",
 	Short: "Run the MCP server",
-	Long:  `Run the MCP server with specified configuration. The server uses stdio transport for communication.`,
+	Long: `Run the MCP server with specified configuration. The server uses stdio transport for communication.
+
+The server is confined to the directory given by --root, then by the GUESSTIMATE_MCP_ROOT
+environment variable (default: current working directory), and refuses to load estimation
+files larger than --max-file-size.
+
+The root is remembered for the next server, started with --root last. The remembered
+directory must still exist.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		rootDir := mcpRootDir
-		if rootDir == "" {
-			var err error
-			rootDir, err = os.Getwd()
-			if err != nil {
-				return fmt.Errorf("failed to get current working directory: %w", err)
-			}
+		rootDir, err := resolveMCPRoot(rootDir)
+		if err != nil {
+			return err
+		}
+		if err := saveLastMCPRoot(rootDir); err != nil {
+			infof("Warning: failed to remember the MCP root: %v\n", err)
 		}
 
 		// Load configuration from the global config file (outside chroot)
@@ -50,10 +51,18 @@ var mcpServerCmd = &cobra.Command{
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
+		// A zero size disables the limit on the command line, where the server
+		// options use it for the default limit
+		serverMaxFileSize := maxFileSize
+		if serverMaxFileSize == 0 {
+			serverMaxFileSize = -1
+		}
+
 		// Create the MCP server with the loaded config
 		server, err := mcp.NewServer(&mcp.ServerOptions{
-			RootDir: rootDir,
-			Config:  config,
+			RootDir:     rootDir,
+			Config:      config,
+			MaxFileSize: serverMaxFileSize,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create MCP server: %w", err)
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mcpRootEnvVar is the environment variable giving the root of the MCP server started
+// without --root
+const mcpRootEnvVar = "GUESSTIMATE_MCP_ROOT"
+
+// lastMCPRoot is the --root value designating the root of the previous MCP server
+const lastMCPRoot = "last"
+
+// resolveMCPRoot returns the root directory of the MCP server, looking in order for the
+// --root flag, "last" designating the remembered root, the GUESSTIMATE_MCP_ROOT
+// environment variable and the current working directory
+func resolveMCPRoot(root string) (string, error) {
+	switch {
+	case root == lastMCPRoot:
+		last, err := loadLastMCPRoot()
+		if err != nil {
+			return "", err
+		}
+		root = last
+	case root == "":
+		root = os.Getenv(mcpRootEnvVar)
+	}
+
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		return wd, nil
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid root '%s': %w", root, err)
+	}
+	return root, nil
+}
+
+// loadLastMCPRoot returns the remembered root of the previous MCP server, checking that
+// it is still a directory
+func loadLastMCPRoot() (string, error) {
+	path, err := mcpRootStateFile()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("no remembered MCP root, start the server once with --root <dir>")
+		}
+		return "", fmt.Errorf("failed to read the remembered MCP root: %w", err)
+	}
+
+	root := strings.TrimSpace(string(data))
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("remembered MCP root '%s' is no longer a directory, use --root <dir>", root)
+	}
+
+	return root, nil
+}
+
+// saveLastMCPRoot remembers the root of the MCP server for --root last
+func saveLastMCPRoot(root string) error {
+	path, err := mcpRootStateFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(root+"\n"), 0o644)
+}
+
+// mcpRootStateFile returns the path of the file remembering the MCP server root,
+// located in $XDG_STATE_HOME (default: ~/.local/state)
+func mcpRootStateFile() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the state directory: %w", err)
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "guesstimate", "mcp-root"), nil
+}
@@ -0,0 +1,261 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// PortfolioProject represents the contribution of a single estimation to the portfolio,
+// its cost being omitted when the costs are not shown
+type PortfolioProject struct {
+	File              string   `json:"file" yaml:"file"`
+	Label             string   `json:"label" yaml:"label"`
+	Tasks             int      `json:"tasks" yaml:"tasks"`
+	WeightedMean      float64  `json:"weightedMean" yaml:"weightedMean"`
+	StandardDeviation float64  `json:"standardDeviation" yaml:"standardDeviation"`
+	Share             float64  `json:"share" yaml:"share"`
+	Cost              *float64 `json:"cost,omitempty" yaml:"cost,omitempty"`
+}
+
+// PortfolioInterval represents a portfolio-level confidence interval
+type PortfolioInterval struct {
+	Level   string   `json:"level" yaml:"level"`
+	MinTime float64  `json:"minTime" yaml:"minTime"`
+	MaxTime float64  `json:"maxTime" yaml:"maxTime"`
+	MinCost *float64 `json:"minCost,omitempty" yaml:"minCost,omitempty"`
+	MaxCost *float64 `json:"maxCost,omitempty" yaml:"maxCost,omitempty"`
+}
+
+// PortfolioReport represents the aggregated report of all estimations in a directory, the
+// costs and currency being omitted when the costs are not shown
+type PortfolioReport struct {
+	Projects          []PortfolioProject  `json:"projects" yaml:"projects"`
+	WeightedMean      float64             `json:"weightedMean" yaml:"weightedMean"`
+	StandardDeviation float64             `json:"standardDeviation" yaml:"standardDeviation"`
+	Cost              *float64            `json:"cost,omitempty" yaml:"cost,omitempty"`
+	Intervals         []PortfolioInterval `json:"intervals" yaml:"intervals"`
+	Currency          string              `json:"currency,omitempty" yaml:"currency,omitempty"`
+	TimeUnit          string              `json:"timeUnit" yaml:"timeUnit"`
+}
+
+// portfolioCmd represents the portfolio command
+var portfolioCmd = &cobra.Command{
+	Use:   "portfolio <directory>",
+	Short: "Show an aggregated summary of all estimations in a directory",
+	Long: `Load every estimation file under the given directory, its subdirectories
+included except hidden ones, and report portfolio-level confidence intervals and
+costs, along with each project's contribution.
+
+Use --tag to only aggregate estimations carrying all the given tags.
+
+Projects are assumed to be independent: weighted means are summed and variances combined.
+Each project is computed with its own params, so that all of them must share the same
+time unit, and the ones with costs the same currency. Projects without costs, e.g. in
+story points, are left out of the portfolio costs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+
+		s := getStore()
+
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		files, err := s.WalkEstimations(dir)
+		if err != nil {
+			return fmt.Errorf("failed to list estimations: %w", err)
+		}
+
+		estimations := make([]*model.Estimation, 0, len(files))
+		loadedFiles := make([]string, 0, len(files))
+		for _, file := range files {
+			estimation, err := s.LoadEstimation(filepath.Join(dir, file))
+			if err != nil {
+				return fmt.Errorf("failed to load estimation '%s': %w", file, err)
+			}
+			if !hasAllTags(estimation, tags) {
+				continue
+			}
+			estimations = append(estimations, estimation)
+			loadedFiles = append(loadedFiles, file)
+		}
+
+		if len(estimations) == 0 {
+			infof("No estimation files found.\n")
+			return nil
+		}
+
+		report, err := buildPortfolioReport(loadedFiles, estimations, config)
+		if err != nil {
+			return withCode(errCodeValidationFailed, err)
+		}
+
+		formatType, _ := cmd.Flags().GetString("format")
+
+		switch formatType {
+		case "json":
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal to JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		case "yaml":
+			data, err := yaml.Marshal(report)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to YAML: %w", err)
+			}
+			fmt.Print(string(data))
+		default:
+			printPortfolioReport(report)
+		}
+
+		return nil
+	},
+}
+
+// buildPortfolioReport aggregates the given estimations into a portfolio report, each one
+// with the configuration merged with its params. The estimations must share the time unit,
+// and the ones with costs the currency.
+func buildPortfolioReport(files []string, estimations []*model.Estimation, config *model.Config) (*PortfolioReport, error) {
+	report := &PortfolioReport{
+		Projects: make([]PortfolioProject, 0, len(estimations)),
+	}
+
+	var totalCost float64
+	var timeUnitFile, currencyFile string
+	hasCosts := false
+	results := make([]stats.EstimationResult, 0, len(estimations))
+	for i, estimation := range estimations {
+		projectConfig := config.WithParams(estimation.Params)
+
+		if timeUnitFile == "" {
+			report.TimeUnit, timeUnitFile = projectConfig.TimeUnit.Acronym, files[i]
+		} else if projectConfig.TimeUnit.Acronym != report.TimeUnit {
+			return nil, fmt.Errorf("%s uses the time unit '%s' instead of '%s' like %s, use --tag to aggregate them separately",
+				files[i], projectConfig.TimeUnit.Acronym, report.TimeUnit, timeUnitFile)
+		}
+
+		projectEst := stats.CalculateProjectEstimationFor(estimation, projectConfig)
+
+		results = append(results, projectEst)
+		project := PortfolioProject{
+			File:              files[i],
+			Label:             estimation.Label,
+			Tasks:             len(estimation.Tasks),
+			WeightedMean:      projectEst.WeightedMean,
+			StandardDeviation: projectEst.StandardDeviation,
+		}
+		if projectConfig.HasCosts() {
+			if !hasCosts {
+				report.Currency, currencyFile = projectConfig.Currency, files[i]
+			} else if projectConfig.Currency != report.Currency {
+				return nil, fmt.Errorf("%s uses the currency '%s' instead of '%s' like %s, use --tag to aggregate them separately",
+					files[i], projectConfig.Currency, report.Currency, currencyFile)
+			}
+			hasCosts = true
+
+			cost := stats.CalculateExpectedCost(estimation, projectConfig)
+			totalCost += cost
+			project.Cost = &cost
+		}
+		report.Projects = append(report.Projects, project)
+	}
+	if hasCosts {
+		report.Cost = &totalCost
+	}
+
+	portfolioEst := stats.CombineEstimations(results...)
+	report.WeightedMean = portfolioEst.WeightedMean
+	report.StandardDeviation = portfolioEst.StandardDeviation
+
+	for i := range report.Projects {
+		if portfolioEst.WeightedMean > 0 {
+			report.Projects[i].Share = (report.Projects[i].WeightedMean / portfolioEst.WeightedMean) * 100
+		}
+	}
+
+	// Costs scale with time using the portfolio average cost per time unit
+	costPerTimeUnit := 0.0
+	if portfolioEst.WeightedMean > 0 {
+		costPerTimeUnit = totalCost / portfolioEst.WeightedMean
+	}
+
+	for _, cl := range stats.GetConfidenceLevels(config) {
+		minTime := math.Max(0, portfolioEst.WeightedMean-portfolioEst.StandardDeviation*cl.Multiplier)
+		maxTime := portfolioEst.WeightedMean + portfolioEst.StandardDeviation*cl.Multiplier
+		interval := PortfolioInterval{
+			Level:   cl.Name,
+			MinTime: minTime,
+			MaxTime: maxTime,
+		}
+		if hasCosts {
+			minCost, maxCost := minTime*costPerTimeUnit, maxTime*costPerTimeUnit
+			interval.MinCost, interval.MaxCost = &minCost, &maxCost
+		}
+		report.Intervals = append(report.Intervals, interval)
+	}
+
+	return report, nil
+}
+
+// hasAllTags returns true if the estimation carries every given tag
+func hasAllTags(estimation *model.Estimation, tags []string) bool {
+	for _, tag := range tags {
+		if !estimation.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// printPortfolioReport prints the portfolio report as text
+func printPortfolioReport(report *PortfolioReport) {
+	fmt.Printf("Projects: %d\n", len(report.Projects))
+	fmt.Println()
+
+	fmt.Println("Project Contributions:")
+	for _, project := range report.Projects {
+		fmt.Printf("  %s - %s: %.2f ± %.2f %s (%.1f%%",
+			project.File, project.Label,
+			project.WeightedMean, project.StandardDeviation, report.TimeUnit,
+			project.Share)
+		if project.Cost != nil {
+			fmt.Printf(", %.2f %s", *project.Cost, report.Currency)
+		}
+		fmt.Println(")")
+	}
+	fmt.Println()
+
+	fmt.Println("Portfolio Time Estimation:")
+	for _, interval := range report.Intervals {
+		fmt.Printf("  %s confidence: %.2f - %.2f %s\n", interval.Level, interval.MinTime, interval.MaxTime, report.TimeUnit)
+	}
+
+	if report.Cost == nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Portfolio Cost Estimation:")
+	fmt.Printf("  Expected: %.2f %s (%.2f %s)\n", *report.Cost, report.Currency, report.WeightedMean, report.TimeUnit)
+	for _, interval := range report.Intervals {
+		fmt.Printf("  %s confidence: %.2f - %.2f %s\n", interval.Level, *interval.MinCost, *interval.MaxCost, report.Currency)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(portfolioCmd)
+
+	portfolioCmd.Flags().StringP("format", "f", "text", "Output format (text, json, yaml)")
+	portfolioCmd.Flags().StringSliceP("tag", "t", nil, "Only aggregate estimations with this tag (repeatable)")
+}
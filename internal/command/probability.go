@@ -0,0 +1,91 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// probabilityCmd represents the probability command
+var probabilityCmd = &cobra.Command{
+	Use:   "probability <file> <value>",
+	Short: "Show the probability of finishing within a given total",
+	Long: `Show the probability that the project total doesn't exceed the given value,
+expressed in the configured time unit, using the normal approximation of the
+project mean and standard deviation.
+
+The value is also placed among the confidence intervals, e.g. to answer
+"can you do it in 40 days?".`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+
+		value, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s': expected a number", args[1])
+		}
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		projectEst := stats.CalculateProjectEstimationFor(estimation, config)
+		if projectEst.IsEmpty() {
+			infof("No estimates yet\n")
+			return nil
+		}
+
+		unit := config.TimeUnit.Acronym
+		z := projectEst.ZScore(value)
+
+		fmt.Printf("Project: %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation, unit)
+		fmt.Printf("Probability of a total <= %.2f %s: %.1f%%\n", value, unit, projectEst.Probability(value)*100)
+
+		if math.IsInf(z, 0) {
+			return nil
+		}
+
+		// Place the value among the two-sided confidence intervals
+		switch {
+		case z > 0:
+			fmt.Printf("%.2f %s is %.2f SD above the mean, the upper bound of the %.1f%% confidence interval\n",
+				value, unit, z, math.Erf(z/math.Sqrt2)*100)
+		case z < 0:
+			fmt.Printf("%.2f %s is %.2f SD below the mean, the lower bound of the %.1f%% confidence interval\n",
+				value, unit, -z, math.Erf(-z/math.Sqrt2)*100)
+		default:
+			fmt.Printf("%.2f %s is the mean\n", value, unit)
+		}
+
+		levels := stats.GetConfidenceLevels(config)
+		for i := len(levels) - 1; i >= 0; i-- {
+			if math.Abs(z) <= levels[i].Multiplier {
+				fmt.Printf("Within the %s confidence interval (%.2f - %.2f %s)\n", levels[i].Name,
+					projectEst.WeightedMean-projectEst.StandardDeviation*levels[i].Multiplier,
+					projectEst.WeightedMean+projectEst.StandardDeviation*levels[i].Multiplier, unit)
+				return nil
+			}
+		}
+		fmt.Printf("Outside of the %s confidence interval\n", levels[0].Name)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(probabilityCmd)
+}
@@ -3,13 +3,20 @@ package command
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/bornholm/guesstimate/internal/store"
+	"github.com/bornholm/guesstimate/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	configFile string
+	configFile  string
+	rootDir     string
+	dryRun      bool
+	jsonErrors  bool
+	maxFileSize int64
+	verbose     bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -25,21 +32,92 @@ It allows you to:
 - Generate markdown reports
 
 Use "guesstimate [command] --help" for more information about a command.`,
+	Version: version.Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Only the MCP server remembers its root, elsewhere "last" would be a directory
+		if rootDir == lastMCPRoot && cmd != mcpServerCmd {
+			return fmt.Errorf("--root %s is only supported by 'mcp server', use --root ./%s for a directory named so", lastMCPRoot, lastMCPRoot)
+		}
+		// Cobra only checks the required flags after this hook, too late to show the usage
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+		argsParsed = true
+		return nil
+	},
+	// Errors are printed by Execute, once and in the requested form
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
+// argsParsed is set once the flags and arguments of the command are parsed and valid,
+// the errors returned before being usage errors
+var argsParsed bool
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	// The flag may not have been parsed when the parsing failed
+	if !argsParsed && slices.ContainsFunc(os.Args[1:], isJSONErrorsFlag) {
+		jsonErrors = true
+	}
+
+	switch {
+	case jsonErrors:
+		writeJSONError(os.Stderr, err)
+	case !argsParsed:
+		fmt.Fprintf(os.Stderr, "Error: %v\n%s", err, cmd.UsageString())
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	}
+	os.Exit(1)
+}
+
+// isJSONErrorsFlag returns true if the argument enables the --json-errors flag
+func isJSONErrorsFlag(arg string) bool {
+	return arg == "--json-errors" || arg == "--json-errors=true"
+}
+
+// infof prints an informational message on stderr, keeping stdout for the command output
+func infof(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format, args...)
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", store.DefaultConfigFile, "configuration file path")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "configuration file path (default: $GUESSTIMATE_CONFIG, then .guesstimate.yml searched upwards, then $XDG_CONFIG_HOME/guesstimate/config.yml)")
+	rootCmd.PersistentFlags().StringVar(&rootDir, "root", "", "restrict estimation files to this directory (default: unrestricted)")
+	rootCmd.PersistentFlags().Int64Var(&maxFileSize, "max-file-size", store.DefaultMaxFileSize, "maximum size of the estimation files to load, in bytes (0: unlimited)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "preview changes without saving them")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "print warnings about the issues repaired while loading files")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "print errors as JSON objects on stderr")
+}
+
+// getStore creates a new YAML store with the configured file, root directory and file size limit
+func getStore() store.Store {
+	return getStoreFor(configFile)
+}
+
+// getStoreFor creates a new store like getStore, reading and writing the given config file
+func getStoreFor(configFile string) store.Store {
+	return newStore(configFile)
+}
+
+// newStore creates the store of the commands, a YAML one honoring the global flags. Tests
+// replace it, e.g. with a store.MemoryStore.
+var newStore = func(configFile string) store.Store {
+	return store.NewRootedYAMLStore(configFile, rootDir).WithConfigCache().WithMaxFileSize(maxFileSize).WithWarnings(warnVerbose)
 }
 
-// getStore creates a new YAML store with the configured file
-func getStore() *store.YAMLStore {
-	return store.NewYAMLStore(configFile)
+// warnVerbose prints a warning on stderr in verbose mode
+func warnVerbose(message string) {
+	if verbose {
+		infof("warning: %s\n", message)
+	}
 }
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// taskScaleCmd represents the task scale command
+var taskScaleCmd = &cobra.Command{
+	Use:   "scale <file>",
+	Short: "Scale the estimates of the tasks",
+	Long: `Multiply the optimistic, likely and pessimistic estimates of every task by a
+calibration factor, e.g. 1.3 after a retrospective showed a 30% underestimation.
+
+Use --category to only scale the tasks of a category, and --dry-run to preview
+the changes. The caps of time-boxed tasks are scaled too, and scaled values are
+rounded to the hundredth. Nothing is saved when
+no estimate changes, e.g. with a factor of 1.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		factor, _ := cmd.Flags().GetFloat64("factor")
+		category, _ := cmd.Flags().GetString("category")
+
+		if factor <= 0 {
+			return fmt.Errorf("invalid factor %g: must be > 0", factor)
+		}
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		matched, scaled := 0, 0
+		for _, task := range estimation.GetOrderedTasks() {
+			if category != "" && task.Category != category {
+				continue
+			}
+			matched++
+
+			// Tasks whose estimates are unchanged keep their update time
+			scaledTask := task.Clone()
+			if !scaledTask.Scale(factor) {
+				continue
+			}
+			estimation.UpdateTask(scaledTask)
+			scaled++
+		}
+
+		if matched == 0 {
+			if category != "" {
+				return fmt.Errorf("no task of category '%s' to scale", category)
+			}
+			return fmt.Errorf("no task to scale")
+		}
+
+		if scaled == 0 {
+			infof("No estimate changed by a factor of %g, nothing to save\n", factor)
+			return nil
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		if dryRun {
+			infof("%d task(s) would be scaled by %g\n", scaled, factor)
+		} else {
+			infof("%d task(s) scaled by %g\n", scaled, factor)
+		}
+		printDelta("Project mean:", stats.CalculateProjectEstimationFor(original, config).WeightedMean,
+			stats.CalculateProjectEstimationFor(estimation, config).WeightedMean, config.TimeUnit.Acronym)
+
+		return nil
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskScaleCmd)
+
+	taskScaleCmd.Flags().Float64("factor", 0, "Factor the estimates are multiplied by (e.g. 1.3)")
+	taskScaleCmd.Flags().String("category", "", "Only scale the tasks of this category")
+	taskScaleCmd.Flags().Bool("force", false, "Allow modifying a locked estimation")
+	_ = taskScaleCmd.MarkFlagRequired("factor")
+}
@@ -0,0 +1,33 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// loadEstimationSpec reads a spec file, decoding it as JSON or YAML depending on its extension
+func loadEstimationSpec(path string) (*model.EstimationSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &model.EstimationSpec{}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON spec: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML spec: %w", err)
+		}
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag <file> [add|remove <tag>...]",
+	Short: "Manage estimation tags",
+	Long: `Manage the tags of an estimation file. Without action, list the current tags.
+
+Examples:
+  guesstimate tag my-project.estimation.yml add Q1 client-x
+  guesstimate tag my-project.estimation.yml remove Q1`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("requires an estimation file")
+		}
+		if len(args) == 1 {
+			return nil
+		}
+		if args[1] != "add" && args[1] != "remove" {
+			return fmt.Errorf("unknown action '%s', expected 'add' or 'remove'", args[1])
+		}
+		if len(args) < 3 {
+			return fmt.Errorf("action '%s' requires at least one tag", args[1])
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		if len(args) == 1 {
+			if len(estimation.Tags) == 0 {
+				infof("No tags found.\n")
+				return nil
+			}
+			fmt.Println("Tags:")
+			for _, tag := range estimation.Tags {
+				fmt.Printf("  %s\n", tag)
+			}
+			return nil
+		}
+
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		action := args[1]
+		for _, tag := range args[2:] {
+			switch action {
+			case "add":
+				if !estimation.AddTag(tag) {
+					infof("Tag '%s' already present\n", tag)
+					continue
+				}
+				infof("Tag '%s' added\n", tag)
+			case "remove":
+				if !estimation.RemoveTag(tag) {
+					return fmt.Errorf("tag '%s' not found", tag)
+				}
+				infof("Tag '%s' removed\n", tag)
+			}
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+
+	tagCmd.Flags().Bool("force", false, "Allow modifying a locked estimation")
+}
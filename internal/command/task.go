@@ -3,7 +3,10 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/spf13/cobra"
@@ -20,21 +23,42 @@ var taskCmd = &cobra.Command{
 var taskAddCmd = &cobra.Command{
 	Use:   "add <file> <label>",
 	Short: "Add a new task",
-	Long:  `Add a new task to an estimation file.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Add a new task to an estimation file.
+
+The task is added at the end of the ordering, unless placed with --after, --before
+or --position (starting at 1).
+
+With --preview, the estimates resulting from the auto-completion of the missing
+values are printed and nothing is saved.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		file := args[0]
 		label := args[1]
+		preview, _ := cmd.Flags().GetBool("preview")
 
 		s := getStore()
 
-		// Load or create estimation
-		estimation, created, err := s.LoadOrCreateEstimation(file, file)
+		// Load or create estimation, a preview never creating the file
+		load := loadOrCreateEstimation
+		if preview {
+			load = loadOrNewEstimation
+		}
+		estimation, created, err := load(s, file)
 		if err != nil {
 			return fmt.Errorf("failed to load estimation: %w", err)
 		}
+		original := estimation.Clone()
 		if created {
-			fmt.Printf("Created new estimation file: %s\n", file)
+			original = nil
+			if !dryRun && !preview {
+				infof("Created new estimation file: %s\n", file)
+			}
+		}
+
+		if !preview {
+			if err := checkUnlocked(cmd, estimation); err != nil {
+				return err
+			}
 		}
 
 		// Load config to get default category
@@ -42,44 +66,102 @@ var taskAddCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		config = config.WithParams(estimation.Params)
 
 		// Get flags
 		category, _ := cmd.Flags().GetString("category")
+		assignee, _ := cmd.Flags().GetString("assignee")
 		optimistic, _ := cmd.Flags().GetFloat64("optimistic")
 		likely, _ := cmd.Flags().GetFloat64("likely")
 		pessimistic, _ := cmd.Flags().GetFloat64("pessimistic")
 
 		// Use default category if not specified
 		if category == "" {
-			category = config.GetFirstCategoryID()
+			if category, err = config.GetDefaultCategoryID(); err != nil {
+				return err
+			}
+		}
+
+		index, err := estimation.ResolvePosition(taskPosition(cmd))
+		if err != nil {
+			return err
 		}
 
 		// Create task
 		task := model.NewTask(label, category)
-		task.SetEstimations(optimistic, likely, pessimistic, config.GetAutoEstimationMultiplier())
+		task.Assignee = assignee
+		if maxEstimate, _ := cmd.Flags().GetFloat64("max-estimate"); maxEstimate > 0 {
+			task.MaxEstimate = &maxEstimate
+		}
+		task.Confidence, _ = cmd.Flags().GetString("confidence")
+		if err := checkTaskConfidence(config, task.Confidence); err != nil {
+			return err
+		}
+		task.FixedCost, _ = cmd.Flags().GetFloat64("fixed-cost")
+		if task.FixedCost < 0 {
+			return fmt.Errorf("invalid fixed cost %g: must be >= 0", task.FixedCost)
+		}
+		task.Tags, _ = cmd.Flags().GetStringSlice("tag")
 
-		// Add task to estimation
-		estimation.AddTask(task)
+		if estimateRange, _ := cmd.Flags().GetString("range"); estimateRange != "" {
+			o, p, err := model.ParseRange(estimateRange)
+			if err != nil {
+				return err
+			}
+			task.SetRange(o, p, config.GetAutoEstimationMultiplier())
+		} else {
+			task.SetEstimations(optimistic, likely, pessimistic, config.GetAutoEstimationMultiplier())
+		}
+
+		if preview {
+			printTaskPreview(task, config)
+			return nil
+		}
+
+		// Add task to estimation at the requested position
+		estimation.InsertTask(task, index)
 
 		// Save estimation
-		if err := s.SaveEstimation(file, estimation); err != nil {
-			return fmt.Errorf("failed to save estimation: %w", err)
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
 		}
 
-		fmt.Printf("Task '%s' added with ID %s\n", label, task.ID)
+		if !dryRun {
+			infof("Task '%s' added with ID %s\n", label, task.ID)
+		}
 		return nil
 	},
 }
 
+// taskPosition returns the position of the task given by the --after, --before and
+// --position flags, as expected by model.Estimation.ResolvePosition
+func taskPosition(cmd *cobra.Command) string {
+	if after, _ := cmd.Flags().GetString("after"); after != "" {
+		return "after:" + after
+	}
+	if before, _ := cmd.Flags().GetString("before"); before != "" {
+		return "before:" + before
+	}
+	if cmd.Flags().Changed("position") {
+		position, _ := cmd.Flags().GetInt("position")
+		return strconv.Itoa(position)
+	}
+	return ""
+}
+
 // taskUpdateCmd represents the task update command
 var taskUpdateCmd = &cobra.Command{
 	Use:   "update <file> <task-id>",
 	Short: "Update a task",
-	Long:  `Update an existing task in an estimation file.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Update an existing task in an estimation file.
+
+With --preview, the estimates resulting from the auto-completion of the missing
+values are printed and nothing is saved.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		file := args[0]
 		taskID := model.TaskID(args[1])
+		preview, _ := cmd.Flags().GetBool("preview")
 
 		s := getStore()
 
@@ -88,16 +170,24 @@ var taskUpdateCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load estimation: %w", err)
 		}
+		original := estimation.Clone()
+
+		if !preview {
+			if err := checkUnlocked(cmd, estimation); err != nil {
+				return err
+			}
+		}
 
 		// Find task
 		task, ok := estimation.Tasks[taskID]
 		if !ok {
-			return fmt.Errorf("task with ID '%s' not found", taskID)
+			return taskNotFoundError(taskID)
 		}
 
 		// Get flags
 		label, _ := cmd.Flags().GetString("label")
 		category, _ := cmd.Flags().GetString("category")
+		assignee, _ := cmd.Flags().GetString("assignee")
 		optimistic, _ := cmd.Flags().GetFloat64("optimistic")
 		likely, _ := cmd.Flags().GetFloat64("likely")
 		pessimistic, _ := cmd.Flags().GetFloat64("pessimistic")
@@ -109,19 +199,51 @@ var taskUpdateCmd = &cobra.Command{
 		if category != "" {
 			task.Category = category
 		}
+		if cmd.Flags().Changed("assignee") {
+			task.Assignee = assignee
+		}
+		if cmd.Flags().Changed("max-estimate") {
+			task.MaxEstimate = nil
+			if maxEstimate, _ := cmd.Flags().GetFloat64("max-estimate"); maxEstimate > 0 {
+				task.MaxEstimate = &maxEstimate
+			}
+		}
 
 		// Load config for multiplier
 		config, err := s.LoadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		config = config.WithParams(estimation.Params)
+
+		if cmd.Flags().Changed("confidence") {
+			task.Confidence, _ = cmd.Flags().GetString("confidence")
+			if err := checkTaskConfidence(config, task.Confidence); err != nil {
+				return err
+			}
+		}
+		if cmd.Flags().Changed("fixed-cost") {
+			task.FixedCost, _ = cmd.Flags().GetFloat64("fixed-cost")
+			if task.FixedCost < 0 {
+				return fmt.Errorf("invalid fixed cost %g: must be >= 0", task.FixedCost)
+			}
+		}
+		if cmd.Flags().Changed("tag") {
+			task.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		}
 
 		// Check if any estimation flags were provided and update with constraints
 		optimisticSet := cmd.Flags().Changed("optimistic")
 		likelySet := cmd.Flags().Changed("likely")
 		pessimisticSet := cmd.Flags().Changed("pessimistic")
 
-		if optimisticSet || likelySet || pessimisticSet {
+		if estimateRange, _ := cmd.Flags().GetString("range"); estimateRange != "" {
+			o, p, err := model.ParseRange(estimateRange)
+			if err != nil {
+				return err
+			}
+			task.SetRange(o, p, config.GetAutoEstimationMultiplier())
+		} else if optimisticSet || likelySet || pessimisticSet {
 			// Get current values if not set
 			o := task.Estimations.Optimistic
 			l := task.Estimations.Likely
@@ -140,16 +262,39 @@ var taskUpdateCmd = &cobra.Command{
 			task.SetEstimations(o, l, p, config.GetAutoEstimationMultiplier())
 		}
 
+		if preview {
+			printTaskPreview(task, config)
+			return nil
+		}
+
+		// An update changing nothing leaves the file and its update time untouched
+		if taskUnchanged(original.Tasks[taskID], task) {
+			infof("Task %s unchanged, nothing to save\n", taskID)
+			return nil
+		}
+
+		estimation.UpdateTask(task)
+
 		// Save estimation
-		if err := s.SaveEstimation(file, estimation); err != nil {
-			return fmt.Errorf("failed to save estimation: %w", err)
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
 		}
 
-		fmt.Printf("Task %s updated\n", taskID)
+		if !dryRun {
+			infof("Task %s updated\n", taskID)
+		}
 		return nil
 	},
 }
 
+// taskUnchanged reports whether a task only differs from its previous version by its
+// update time
+func taskUnchanged(previous *model.Task, task *model.Task) bool {
+	compared := task.Clone()
+	compared.UpdatedAt = previous.UpdatedAt
+	return reflect.DeepEqual(previous, compared)
+}
+
 // taskRemoveCmd represents the task remove command
 var taskRemoveCmd = &cobra.Command{
 	Use:   "remove <file> <task-id>",
@@ -167,34 +312,46 @@ var taskRemoveCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load estimation: %w", err)
 		}
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
 
 		// Check if task exists
 		if _, ok := estimation.Tasks[taskID]; !ok {
-			return fmt.Errorf("task with ID '%s' not found", taskID)
+			return taskNotFoundError(taskID)
 		}
 
 		// Remove task
 		estimation.RemoveTask(taskID)
 
 		// Save estimation
-		if err := s.SaveEstimation(file, estimation); err != nil {
-			return fmt.Errorf("failed to save estimation: %w", err)
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
 		}
 
-		fmt.Printf("Task %s removed\n", taskID)
+		if !dryRun {
+			infof("Task %s removed\n", taskID)
+		}
 		return nil
 	},
 }
 
 // taskListCmd represents the task list command
 var taskListCmd = &cobra.Command{
-	Use:   "list <file>",
+	Use:   "list [file]",
 	Short: "List tasks",
-	Long:  `List all tasks in an estimation file.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `List all tasks in an estimation file.
+
+Use --min-mean and --category to only list the tasks whose weighted mean is at
+least the given value and which belong to the given category.`,
+	Args: optionalFileArgs(cobra.ExactArgs(1)),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		file := args[0]
+		file := withDefaultFile(args)[0]
 		format, _ := cmd.Flags().GetString("format")
+		minMean, _ := cmd.Flags().GetFloat64("min-mean")
+		category, _ := cmd.Flags().GetString("category")
 
 		s := getStore()
 
@@ -209,30 +366,51 @@ var taskListCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		config = config.WithParams(estimation.Params)
 
-		if len(estimation.Tasks) == 0 {
-			fmt.Println("No tasks found.")
-			return nil
-		}
+		// Filter the ordered tasks, preserving their order
+		tasks := slices.DeleteFunc(estimation.GetOrderedTasks(), func(task *model.Task) bool {
+			return task.WeightedMean() < minMean || (category != "" && task.Category != category)
+		})
 
 		switch format {
 		case "json":
-			tasks := estimation.GetOrderedTasks()
 			data, err := json.MarshalIndent(tasks, "", "  ")
 			if err != nil {
 				return fmt.Errorf("failed to marshal tasks to JSON: %w", err)
 			}
 			fmt.Println(string(data))
 		default:
+			if len(estimation.Tasks) == 0 {
+				infof("No tasks found.\n")
+				return nil
+			}
+			if len(tasks) == 0 {
+				infof("No tasks match the filters.\n")
+				return nil
+			}
+
 			fmt.Println("Tasks:")
-			for _, task := range estimation.GetOrderedTasks() {
+			for _, task := range tasks {
 				cat := config.GetTaskCategory(task.Category)
 				mean := task.WeightedMean()
-				sd := task.StandardDeviation()
+				sd := task.StandardDeviationWith(config.GetEstimationModel())
 				fmt.Printf("  [%s] %s (%s)\n", task.ID, task.Label, cat.Label)
 				fmt.Printf("      O: %.2f, L: %.2f, P: %.2f => Mean: %.2f, SD: %.2f\n",
 					task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic,
 					mean, sd)
+				if task.IsCapped() {
+					fmt.Printf("      Capped at %.2f %s\n", *task.MaxEstimate, config.TimeUnit.Acronym)
+				}
+				if task.Confidence != "" {
+					fmt.Printf("      Confidence: %s (SD x%g)\n", task.Confidence, config.GetTaskConfidenceFactor(task.Confidence))
+				}
+				if task.FixedCost > 0 && config.HasCosts() {
+					fmt.Printf("      Fixed cost: %.2f %s\n", task.FixedCost, config.Currency)
+				}
+				if len(task.Tags) > 0 {
+					fmt.Printf("      Tags: %s\n", strings.Join(task.Tags, ", "))
+				}
 			}
 		}
 
@@ -242,16 +420,30 @@ var taskListCmd = &cobra.Command{
 
 // taskMoveCmd represents the task move command
 var taskMoveCmd = &cobra.Command{
-	Use:   "move <file> <task-id> <offset>",
+	Use:   "move <file> <task-id> [offset]",
 	Short: "Move a task",
-	Long:  `Move a task up or down in the ordering. Use negative offset to move up, positive to move down.`,
-	Args:  cobra.ExactArgs(3),
+	Long: `Move a task up or down in the ordering. Use negative offset to move up, positive to move down.
+
+Use --to instead of the offset to move the task to an absolute index, from 0 for the
+top, a negative index counting from the end (-1 for the bottom). Out of range indexes
+are clamped to the top or the bottom.`,
+	Args: cobra.RangeArgs(2, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		file := args[0]
 		taskID := model.TaskID(args[1])
-		offset, err := strconv.Atoi(args[2])
-		if err != nil {
-			return fmt.Errorf("invalid offset: %w", err)
+
+		toIndex := cmd.Flags().Changed("to")
+		if toIndex == (len(args) == 3) {
+			return fmt.Errorf("either an offset or --to must be given")
+		}
+
+		var offset int
+		if !toIndex {
+			var err error
+			offset, err = strconv.Atoi(args[2])
+			if err != nil {
+				return fmt.Errorf("invalid offset: %w", err)
+			}
 		}
 
 		s := getStore()
@@ -261,22 +453,242 @@ var taskMoveCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to load estimation: %w", err)
 		}
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		if _, ok := estimation.Tasks[taskID]; !ok {
+			return taskNotFoundError(taskID)
+		}
 
 		// Move task
+		if toIndex {
+			to, _ := cmd.Flags().GetInt("to")
+			index, ok := estimation.MoveTaskToIndex(taskID, to)
+			if !ok {
+				return fmt.Errorf("task %s is not in the ordering, run 'doctor --fix' to repair it", taskID)
+			}
+
+			if err := saveEstimation(s, file, original, estimation); err != nil {
+				return err
+			}
+
+			if !dryRun {
+				infof("Task %s moved to index %d\n", taskID, index)
+			}
+			return nil
+		}
+
 		if !estimation.MoveTask(taskID, offset) {
 			return fmt.Errorf("failed to move task %s by %d positions", taskID, offset)
 		}
 
 		// Save estimation
-		if err := s.SaveEstimation(file, estimation); err != nil {
-			return fmt.Errorf("failed to save estimation: %w", err)
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
 		}
 
-		fmt.Printf("Task %s moved by %d positions\n", taskID, offset)
+		if !dryRun {
+			infof("Task %s moved by %d positions\n", taskID, offset)
+		}
 		return nil
 	},
 }
 
+// taskSortCmd represents the task sort command
+var taskSortCmd = &cobra.Command{
+	Use:   "sort <file>",
+	Short: "Sort tasks",
+	Long: `Persistently reorder the tasks of an estimation file by category, mean
+(largest first) or label. Tasks with equal keys keep their relative order.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		criterion, _ := cmd.Flags().GetString("by")
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		if err := estimation.SortTasksBy(criterion); err != nil {
+			return err
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			infof("Tasks sorted by %s\n", criterion)
+		}
+		return nil
+	},
+}
+
+// taskDedupCmd represents the task dedup command
+var taskDedupCmd = &cobra.Command{
+	Use:   "dedup <file>",
+	Short: "Detect duplicate tasks",
+	Long: `List the tasks sharing the same label (trimmed, case-insensitive).
+
+With --merge, the duplicates are combined into the first task of each group by
+summing their estimates and fixed costs, and gathering their tags. The caps are
+summed when all the duplicates have one, and dropped otherwise. With --keep-first, the duplicates after the first task
+of each group are removed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		merge, _ := cmd.Flags().GetBool("merge")
+		keepFirst, _ := cmd.Flags().GetBool("keep-first")
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+		original := estimation.Clone()
+
+		duplicates := estimation.DuplicateTasks()
+		if len(duplicates) == 0 {
+			infof("No duplicate tasks found.\n")
+			return nil
+		}
+
+		if !merge && !keepFirst {
+			fmt.Println("Duplicate tasks:")
+			for _, group := range duplicates {
+				fmt.Printf("  %s\n", strings.TrimSpace(group[0].Label))
+				for _, task := range group {
+					fmt.Printf("    [%s] O: %.2f, L: %.2f, P: %.2f\n",
+						task.ID, task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic)
+				}
+			}
+			return nil
+		}
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		for _, group := range duplicates {
+			first := group[0]
+			for _, task := range group[1:] {
+				if merge {
+					first.Merge(task)
+					infof("Task %s merged into %s\n", task.ID, first.ID)
+				} else {
+					infof("Task %s removed (duplicate of %s)\n", task.ID, first.ID)
+				}
+				estimation.RemoveTask(task.ID)
+			}
+			if merge {
+				infof("Task %s now O: %.2f, L: %.2f, P: %.2f\n",
+					first.ID, first.Estimations.Optimistic, first.Estimations.Likely, first.Estimations.Pessimistic)
+			}
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+// taskRenumberCmd represents the task renumber command
+var taskRenumberCmd = &cobra.Command{
+	Use:   "renumber <file>",
+	Short: "Reassign sequential task IDs",
+	Long: `Reassign short sequential IDs (t1, t2, ...) to the tasks, following their current order.
+
+References to the old task IDs made outside of the estimation file will break.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+		original := estimation.Clone()
+
+		if err := checkUnlocked(cmd, estimation); err != nil {
+			return err
+		}
+
+		renamed := estimation.RenumberTasks()
+		if len(renamed) == 0 {
+			if slices.Equal(original.Ordering, estimation.Ordering) {
+				infof("Task IDs are already sequential.\n")
+				return nil
+			}
+
+			// Only the ordering was rewritten
+			if err := saveEstimation(s, file, original, estimation); err != nil {
+				return err
+			}
+			if !dryRun {
+				infof("Task IDs are already sequential, task ordering repaired.\n")
+			}
+			return nil
+		}
+
+		infof("Warning: references to the old task IDs outside of %s will break\n", file)
+
+		// Print the mapping following the new order
+		oldIDs := make(map[model.TaskID]model.TaskID, len(renamed))
+		for oldID, id := range renamed {
+			oldIDs[id] = oldID
+		}
+		for _, id := range estimation.Ordering {
+			if oldID, ok := oldIDs[id]; ok {
+				infof("%s -> %s\n", oldID, id)
+			}
+		}
+
+		// Save estimation
+		if err := saveEstimation(s, file, original, estimation); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+// printTaskPreview prints the estimates of a task that is not saved
+func printTaskPreview(task *model.Task, config *model.Config) {
+	fmt.Printf("O: %.2f, L: %.2f, P: %.2f => Mean: %.2f, SD: %.2f\n",
+		task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic,
+		task.WeightedMean(), task.StandardDeviationWith(config.GetEstimationModel()))
+	fmt.Println("Preview only, nothing was saved.")
+}
+
+// checkTaskConfidence returns an error if the given task confidence level is not configured
+func checkTaskConfidence(config *model.Config, level string) error {
+	if level == "" || config.HasTaskConfidence(level) {
+		return nil
+	}
+	return fmt.Errorf("invalid confidence level '%s', expected one of: %s", level, strings.Join(config.GetTaskConfidenceLevels(), ", "))
+}
+
 func init() {
 	rootCmd.AddCommand(taskCmd)
 	taskCmd.AddCommand(taskAddCmd)
@@ -284,20 +696,63 @@ func init() {
 	taskCmd.AddCommand(taskRemoveCmd)
 	taskCmd.AddCommand(taskListCmd)
 	taskCmd.AddCommand(taskMoveCmd)
+	taskCmd.AddCommand(taskDedupCmd)
+	taskCmd.AddCommand(taskSortCmd)
+	taskCmd.AddCommand(taskRenumberCmd)
 
 	// task add flags
 	taskAddCmd.Flags().String("category", "", "Task category (default: first category in config)")
+	taskAddCmd.Flags().String("assignee", "", "Person assigned to the task")
+	taskAddCmd.Flags().String("after", "", "Insert the task after the task with this ID")
+	taskAddCmd.Flags().String("before", "", "Insert the task before the task with this ID")
+	taskAddCmd.Flags().Int("position", 0, "Insert the task at this position in the ordering, starting at 1")
+	taskAddCmd.MarkFlagsMutuallyExclusive("after", "before", "position")
 	taskAddCmd.Flags().Float64P("optimistic", "o", 0, "Optimistic estimate")
 	taskAddCmd.Flags().Float64P("likely", "l", 0, "Likely estimate")
 	taskAddCmd.Flags().Float64P("pessimistic", "p", 0, "Pessimistic estimate")
+	taskAddCmd.Flags().StringP("range", "r", "", "Estimate range as min-max (e.g. 3-8), likely is the midpoint")
+	taskAddCmd.Flags().Float64("max-estimate", 0, "Cap the estimate of a time-boxed task")
+	taskAddCmd.Flags().String("confidence", "", "Stability of the task requirements (high, medium, low)")
+	taskAddCmd.Flags().Float64("fixed-cost", 0, "Fixed cost of the task unrelated to the effort (e.g. a license)")
+	taskAddCmd.Flags().StringSlice("tag", nil, "Task tag, repeatable or comma separated (e.g. must-have)")
+
+	// task move flags
+	taskMoveCmd.Flags().Int("to", 0, "Move the task to this index instead, from 0 for the top, negative from the end (-1 for the bottom)")
 
 	// task update flags
 	taskUpdateCmd.Flags().StringP("label", "l", "", "New task label")
 	taskUpdateCmd.Flags().String("category", "", "New task category")
+	taskUpdateCmd.Flags().String("assignee", "", "New task assignee (empty to unassign)")
 	taskUpdateCmd.Flags().Float64P("optimistic", "o", 0, "New optimistic estimate")
 	taskUpdateCmd.Flags().Float64("likely", 0, "New likely estimate")
 	taskUpdateCmd.Flags().Float64P("pessimistic", "p", 0, "New pessimistic estimate")
+	taskUpdateCmd.Flags().StringP("range", "r", "", "New estimate range as min-max (e.g. 3-8), likely is the midpoint")
+	taskUpdateCmd.Flags().Float64("max-estimate", 0, "New cap of a time-boxed task (0 to remove the cap)")
+	taskUpdateCmd.Flags().String("confidence", "", "New stability of the task requirements (empty to remove it)")
+	taskUpdateCmd.Flags().Float64("fixed-cost", 0, "New fixed cost of the task (0 to remove it)")
+	taskUpdateCmd.Flags().StringSlice("tag", nil, "New task tags replacing the current ones, repeatable or comma separated (empty to remove them)")
+
+	for _, c := range []*cobra.Command{taskAddCmd, taskUpdateCmd, taskRemoveCmd, taskMoveCmd, taskDedupCmd, taskSortCmd, taskRenumberCmd} {
+		c.Flags().Bool("force", false, "Allow modifying a locked estimation")
+	}
+
+	for _, c := range []*cobra.Command{taskAddCmd, taskUpdateCmd} {
+		c.Flags().Bool("preview", false, "Print the resulting estimates without saving")
+		for _, flag := range []string{"optimistic", "likely", "pessimistic"} {
+			c.MarkFlagsMutuallyExclusive("range", flag)
+		}
+	}
 
 	// task list flags
 	taskListCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	taskListCmd.Flags().Float64("min-mean", 0, "Only list tasks whose weighted mean is at least this value")
+	taskListCmd.Flags().String("category", "", "Only list tasks of this category")
+
+	// Sort flags
+	taskSortCmd.Flags().String("by", model.SortByCategory, "Sort criterion (category, mean, label)")
+
+	// Dedup flags
+	taskDedupCmd.Flags().Bool("merge", false, "Merge duplicates into the first task by summing their estimates")
+	taskDedupCmd.Flags().Bool("keep-first", false, "Remove duplicates, keeping the first task")
+	taskDedupCmd.MarkFlagsMutuallyExclusive("merge", "keep-first")
 }
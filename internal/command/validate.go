@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate an estimation",
+	Long: `Check an estimation for invalid task estimations (errors) and likely
+data-entry mistakes (warnings), such as suspiciously wide ranges or ranges
+without any uncertainty.
+
+Warning thresholds are configured with wideRangeRatio and narrowRangeThreshold,
+a negative value disabling the matching check.`,
+	Args: optionalFileArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := withDefaultFile(args)[0]
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		errors := estimation.Validate()
+		warnings := estimation.Warnings(config)
+
+		if len(errors) == 0 && len(warnings) == 0 {
+			infof("Estimation is valid.\n")
+			return nil
+		}
+
+		if len(errors) > 0 {
+			fmt.Println("Errors:")
+			for _, e := range errors {
+				fmt.Printf("  %s\n", e)
+			}
+		}
+
+		if len(warnings) > 0 {
+			fmt.Println("Warnings:")
+			for _, w := range warnings {
+				fmt.Printf("  %s\n", w)
+			}
+		}
+
+		if len(errors) > 0 {
+			return withCode(errCodeValidationFailed, fmt.Errorf("estimation has %d error(s)", len(errors)))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	Long:  `Print the version, git commit and build date of guesstimate.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("%s %s\n", version.Name, version.String())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+
+	rootCmd.SetVersionTemplate(fmt.Sprintf("%s %s\n", version.Name, version.String()))
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// taskWhatIfCmd represents the task whatif command
+var taskWhatIfCmd = &cobra.Command{
+	Use:   "whatif <file> <task-id>",
+	Short: "Show the impact of a task change",
+	Long: `Show the impact of hypothetical new estimates of a task on the project
+totals and costs, without saving anything.
+
+Missing estimates are auto-filled like with "task update".`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		taskID := model.TaskID(args[1])
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		if _, ok := estimation.Tasks[taskID]; !ok {
+			return taskNotFoundError(taskID)
+		}
+
+		// Apply the change to a copy of the estimation
+		modified := estimation.Clone()
+		task := modified.Tasks[taskID]
+
+		if estimateRange, _ := cmd.Flags().GetString("range"); estimateRange != "" {
+			o, p, err := model.ParseRange(estimateRange)
+			if err != nil {
+				return err
+			}
+			task.SetRange(o, p, config.GetAutoEstimationMultiplier())
+		} else {
+			o, l, p := task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic
+			if cmd.Flags().Changed("optimistic") {
+				o, _ = cmd.Flags().GetFloat64("optimistic")
+			}
+			if cmd.Flags().Changed("likely") {
+				l, _ = cmd.Flags().GetFloat64("likely")
+			}
+			if cmd.Flags().Changed("pessimistic") {
+				p, _ = cmd.Flags().GetFloat64("pessimistic")
+			}
+			task.SetEstimations(o, l, p, config.GetAutoEstimationMultiplier())
+		}
+
+		// Like a dry run, the changes and their impact are reported on stderr
+		infof("What if [%s] %s:\n", task.ID, task.Label)
+		changes := describeChanges(estimation, modified)
+		if len(changes) == 0 {
+			infof("  (no changes)\n")
+		}
+		for _, change := range changes {
+			infof("  %s\n", change)
+		}
+		infof("\n")
+
+		before := stats.CalculateProjectEstimationFor(estimation, config)
+		after := stats.CalculateProjectEstimationFor(modified, config)
+		unit := config.TimeUnit.Acronym
+		printDelta("Project mean:", before.WeightedMean, after.WeightedMean, unit)
+		printDelta("Project SD:", before.StandardDeviation, after.StandardDeviation, unit)
+
+		if !config.HasCosts() {
+			return nil
+		}
+
+		printDelta("Expected cost:", stats.CalculateExpectedCost(estimation, config), stats.CalculateExpectedCost(modified, config), config.Currency)
+
+		costConfidence := stats.CostConfidenceLevel(config)
+		beforeCosts := stats.CalculateMinMaxCosts(estimation, config, costConfidence)
+		afterCosts := stats.CalculateMinMaxCosts(modified, config, costConfidence)
+		printDelta(fmt.Sprintf("Maximum cost (%s):", costConfidence.Name), beforeCosts.Max.TotalCost, afterCosts.Max.TotalCost, config.Currency)
+
+		return nil
+	},
+}
+
+// printDelta prints a value before and after a change, followed by the difference, to
+// stderr along with the other status messages
+func printDelta(label string, before float64, after float64, unit string) {
+	infof("%-22s %.2f %s -> %.2f %s (%+.2f %s)\n", label, before, unit, after, unit, after-before, unit)
+}
+
+func init() {
+	taskCmd.AddCommand(taskWhatIfCmd)
+
+	taskWhatIfCmd.Flags().Float64P("optimistic", "o", 0, "Hypothetical optimistic estimate")
+	taskWhatIfCmd.Flags().Float64P("likely", "l", 0, "Hypothetical likely estimate")
+	taskWhatIfCmd.Flags().Float64P("pessimistic", "p", 0, "Hypothetical pessimistic estimate")
+	taskWhatIfCmd.Flags().StringP("range", "r", "", "Hypothetical estimate range as min-max (e.g. 3-8), likely is the midpoint")
+
+	for _, flag := range []string{"optimistic", "likely", "pessimistic"} {
+		taskWhatIfCmd.MarkFlagsMutuallyExclusive("range", flag)
+	}
+}
@@ -0,0 +1,142 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// workloadImbalanceThreshold is the relative deviation from the average load
+// above which an assignee is reported as over or under loaded
+const workloadImbalanceThreshold = 0.2
+
+// WorkloadItem represents an assignee in the workload report output, its cost being
+// omitted when the costs are not shown
+type WorkloadItem struct {
+	Assignee          string   `json:"assignee" yaml:"assignee"`
+	Tasks             int      `json:"tasks" yaml:"tasks"`
+	WeightedMean      float64  `json:"weightedMean" yaml:"weightedMean"`
+	StandardDeviation float64  `json:"standardDeviation" yaml:"standardDeviation"`
+	Cost              *float64 `json:"cost,omitempty" yaml:"cost,omitempty"`
+	Percentage        float64  `json:"percentage" yaml:"percentage"`
+	Deviation         float64  `json:"deviation" yaml:"deviation"`
+}
+
+// workloadCmd represents the workload command
+var workloadCmd = &cobra.Command{
+	Use:   "workload [file]",
+	Short: "Show the workload of each assignee",
+	Long: `Group the tasks of an estimation by assignee and report each person's total
+weighted mean and cost. Assignees whose load deviates from the average by more
+than 20% are highlighted. Tasks without assignee are grouped under "(unassigned)".`,
+	Args: optionalFileArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := withDefaultFile(args)[0]
+
+		s := getStore()
+
+		// Load estimation
+		estimation, err := s.LoadEstimation(file)
+		if err != nil {
+			return fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		// Load config
+		config, err := s.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		config = config.WithParams(estimation.Params)
+
+		if len(estimation.Tasks) == 0 {
+			infof("No tasks found.\n")
+			return nil
+		}
+
+		workloads := stats.CalculateWorkload(estimation, config)
+
+		// Average load across assignees, ignoring unassigned tasks
+		var assignedTotal float64
+		var assignedCount int
+		for _, workload := range workloads {
+			if workload.Assignee != stats.UnassignedLabel {
+				assignedTotal += workload.WeightedMean
+				assignedCount++
+			}
+		}
+		averageLoad := 0.0
+		if assignedCount > 0 {
+			averageLoad = assignedTotal / float64(assignedCount)
+		}
+
+		items := make([]WorkloadItem, 0, len(workloads))
+		for _, workload := range workloads {
+			var cost *float64
+			if config.HasCosts() {
+				cost = &workload.Cost
+			}
+			deviation := 0.0
+			if workload.Assignee != stats.UnassignedLabel && averageLoad > 0 {
+				deviation = (workload.WeightedMean - averageLoad) / averageLoad * 100
+			}
+			items = append(items, WorkloadItem{
+				Assignee:          workload.Assignee,
+				Tasks:             workload.Tasks,
+				WeightedMean:      workload.WeightedMean,
+				StandardDeviation: workload.StandardDeviation,
+				Cost:              cost,
+				Percentage:        workload.Percentage,
+				Deviation:         deviation,
+			})
+		}
+
+		formatType, _ := cmd.Flags().GetString("format")
+
+		switch formatType {
+		case "json":
+			data, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal to JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		case "yaml":
+			data, err := yaml.Marshal(items)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to YAML: %w", err)
+			}
+			fmt.Print(string(data))
+		default:
+			fmt.Println("Workload:")
+			for _, item := range items {
+				fmt.Printf("  %s: %d tasks, %.2f ± %.2f %s (%.1f%%)",
+					item.Assignee, item.Tasks,
+					item.WeightedMean, item.StandardDeviation, config.TimeUnit.Acronym,
+					item.Percentage)
+				if item.Cost != nil {
+					fmt.Printf(", %.2f %s", *item.Cost, config.Currency)
+				}
+				switch {
+				case item.Deviation > workloadImbalanceThreshold*100:
+					fmt.Printf(" [overloaded: %+.0f%% vs average]", item.Deviation)
+				case item.Deviation < -workloadImbalanceThreshold*100:
+					fmt.Printf(" [underloaded: %+.0f%% vs average]", item.Deviation)
+				}
+				fmt.Println()
+			}
+			if assignedCount > 0 {
+				fmt.Printf("\nAverage load: %.2f %s per assignee\n", averageLoad, config.TimeUnit.Acronym)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workloadCmd)
+
+	workloadCmd.Flags().StringP("format", "f", "text", "Output format (text, json, yaml)")
+}
@@ -0,0 +1,241 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+// ErrTaskNotFound is returned when editing a task that doesn't exist
+var ErrTaskNotFound = errors.New("task not found")
+
+// TaskFields contains the editable fields of a task
+type TaskFields struct {
+	Label       string
+	Description string
+	Category    string
+	Confidence  string
+
+	// Optimistic, Likely and Pessimistic are completed with the auto-estimation
+	// multiplier when missing (0)
+	Optimistic  float64
+	Likely      float64
+	Pessimistic float64
+
+	// Range is an optional "min-max" estimate range, taking precedence over the
+	// three estimates when set
+	Range string
+}
+
+// EstimationEditor applies the task operations of an edit session (add, edit, delete
+// and move) to an estimation, independently of any user interface, so that the
+// interactive editor and scripted callers share the same editing logic
+type EstimationEditor struct {
+	store      store.Store
+	config     *model.Config
+	estimation *model.Estimation
+	path       string
+
+	unsavedChanges bool
+}
+
+// NewEstimationEditor creates an editor of the given estimation, saved to path in the store
+func NewEstimationEditor(s store.Store, config *model.Config, estimation *model.Estimation, path string) *EstimationEditor {
+	return &EstimationEditor{
+		store:      s,
+		config:     config,
+		estimation: estimation,
+		path:       path,
+	}
+}
+
+// Estimation returns the edited estimation
+func (e *EstimationEditor) Estimation() *model.Estimation {
+	return e.estimation
+}
+
+// Path returns the path the estimation is saved to
+func (e *EstimationEditor) Path() string {
+	return e.path
+}
+
+// HasUnsavedChanges returns true if the estimation was modified since it was last saved
+func (e *EstimationEditor) HasUnsavedChanges() bool {
+	return e.unsavedChanges
+}
+
+// Task returns the task with the given ID
+func (e *EstimationEditor) Task(id model.TaskID) (*model.Task, error) {
+	task, ok := e.estimation.Tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return task, nil
+}
+
+// AddTask adds a new task with the given fields at the end of the estimation. An empty
+// category is the first configured one.
+func (e *EstimationEditor) AddTask(fields TaskFields) (*model.Task, error) {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return nil, err
+	}
+
+	if fields.Category == "" {
+		var err error
+		if fields.Category, err = e.config.GetDefaultCategoryID(); err != nil {
+			return nil, err
+		}
+	}
+
+	task := model.NewTask(fields.Label, fields.Category)
+	if err := e.applyFields(task, fields); err != nil {
+		return nil, err
+	}
+
+	e.estimation.AddTask(task)
+	e.unsavedChanges = true
+
+	return task, nil
+}
+
+// UpdateTask replaces the fields of the task with the given ID
+func (e *EstimationEditor) UpdateTask(id model.TaskID, fields TaskFields) (*model.Task, error) {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return nil, err
+	}
+
+	task, err := e.Task(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the fields to a copy, leaving the task untouched on error
+	updated := task.Clone()
+	if err := e.applyFields(updated, fields); err != nil {
+		return nil, err
+	}
+	updated.Label = fields.Label
+	updated.Category = fields.Category
+
+	// An unchanged task keeps its update time, and the estimation its saved state. The
+	// task is cloned too, for its empty slices to compare equal to the ones of the copy.
+	if reflect.DeepEqual(updated, task.Clone()) {
+		return task, nil
+	}
+	*task = *updated
+
+	e.estimation.UpdateTask(task)
+	e.unsavedChanges = true
+
+	return task, nil
+}
+
+// RemoveTask removes the task with the given ID
+func (e *EstimationEditor) RemoveTask(id model.TaskID) error {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return err
+	}
+
+	if _, err := e.Task(id); err != nil {
+		return err
+	}
+
+	e.estimation.RemoveTask(id)
+	e.unsavedChanges = true
+
+	return nil
+}
+
+// MoveTask moves the task with the given ID by offset positions in the ordering
+// (negative is up), and returns true if it actually moved
+func (e *EstimationEditor) MoveTask(id model.TaskID, offset int) (bool, error) {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return false, err
+	}
+
+	if _, err := e.Task(id); err != nil {
+		return false, err
+	}
+
+	if offset == 0 || !e.estimation.MoveTask(id, offset) {
+		return false, nil
+	}
+	e.unsavedChanges = true
+
+	return true, nil
+}
+
+// Rename sets the label of the estimation
+func (e *EstimationEditor) Rename(label string) error {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return err
+	}
+
+	if label == "" {
+		return errors.New("estimation label cannot be empty")
+	}
+
+	if label != e.estimation.Label {
+		e.estimation.Label = label
+		e.estimation.UpdatedAt = time.Now()
+		e.unsavedChanges = true
+	}
+
+	return nil
+}
+
+// Describe sets the description of the estimation
+func (e *EstimationEditor) Describe(description string) error {
+	if err := e.estimation.CheckUnlocked(); err != nil {
+		return err
+	}
+
+	if description != e.estimation.Description {
+		e.estimation.Description = description
+		e.estimation.UpdatedAt = time.Now()
+		e.unsavedChanges = true
+	}
+
+	return nil
+}
+
+// Save saves the estimation to its path in the store
+func (e *EstimationEditor) Save() error {
+	if err := e.store.SaveEstimation(e.path, e.estimation); err != nil {
+		return err
+	}
+	e.unsavedChanges = false
+
+	return nil
+}
+
+// applyFields sets the description, confidence and estimates of the task. The estimates
+// are only completed when changed, so that unchanged stored values are kept as is.
+func (e *EstimationEditor) applyFields(task *model.Task, fields TaskFields) error {
+	if fields.Confidence != "" && !e.config.HasTaskConfidence(fields.Confidence) {
+		return fmt.Errorf("invalid confidence level '%s', expected one of: %s", fields.Confidence, strings.Join(e.config.GetTaskConfidenceLevels(), ", "))
+	}
+
+	task.Description = fields.Description
+	task.Confidence = fields.Confidence
+
+	multiplier := e.config.GetAutoEstimationMultiplier()
+	if rangeText := strings.TrimSpace(fields.Range); rangeText != "" {
+		o, p, err := model.ParseRange(rangeText)
+		if err != nil {
+			return err
+		}
+		if o != task.Estimations.Optimistic || p != task.Estimations.Pessimistic {
+			task.SetRange(o, p, multiplier)
+		}
+	} else if (model.Estimations{Optimistic: fields.Optimistic, Likely: fields.Likely, Pessimistic: fields.Pessimistic}) != task.Estimations {
+		task.SetEstimations(fields.Optimistic, fields.Likely, fields.Pessimistic, multiplier)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
+)
+
+func TestUpdateTask(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		estimations model.Estimations
+		edit        func(fields *TaskFields)
+		want        model.Estimations
+		wantChanged bool
+	}{
+		{
+			// The stored estimates are kept as entered, even out of order
+			name:        "unchanged",
+			estimations: model.Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+			edit:        func(fields *TaskFields) {},
+			want:        model.Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+		},
+		{
+			// The likely estimate is not moved to the middle of an unchanged range
+			name:        "unchanged range",
+			estimations: model.Estimations{Optimistic: 2, Likely: 3, Pessimistic: 5},
+			edit:        func(fields *TaskFields) { fields.Range = "2-5" },
+			want:        model.Estimations{Optimistic: 2, Likely: 3, Pessimistic: 5},
+		},
+		{
+			name:        "relabeled",
+			estimations: model.Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+			edit:        func(fields *TaskFields) { fields.Label = "Sign in" },
+			want:        model.Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+			wantChanged: true,
+		},
+		{
+			name:        "re-estimated",
+			estimations: model.Estimations{Optimistic: 1, Likely: 2, Pessimistic: 4},
+			edit:        func(fields *TaskFields) { fields.Likely = 3 },
+			want:        model.Estimations{Optimistic: 1, Likely: 3, Pessimistic: 4},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimation := model.NewEstimation("Project")
+			task := model.NewTask("Login", "development")
+			task.Estimations = tt.estimations
+			task.UpdatedAt = updatedAt
+			estimation.AddTask(task)
+
+			e := NewEstimationEditor(store.NewMemoryStore(), model.DefaultConfig(), estimation, "project.estimation.yml")
+
+			fields := TaskFields{
+				Label:       task.Label,
+				Category:    task.Category,
+				Optimistic:  task.Estimations.Optimistic,
+				Likely:      task.Estimations.Likely,
+				Pessimistic: task.Estimations.Pessimistic,
+			}
+			tt.edit(&fields)
+
+			updated, err := e.UpdateTask(task.ID, fields)
+			if err != nil {
+				t.Fatalf("UpdateTask() error = %v", err)
+			}
+
+			if updated.Estimations != tt.want {
+				t.Errorf("estimations = %+v, want %+v", updated.Estimations, tt.want)
+			}
+			if changed := e.HasUnsavedChanges(); changed != tt.wantChanged {
+				t.Errorf("HasUnsavedChanges() = %v, want %v", changed, tt.wantChanged)
+			}
+			if touched := !updated.UpdatedAt.Equal(updatedAt); touched != tt.wantChanged {
+				t.Errorf("touched = %v, want %v", touched, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package format
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ShortHashLength is the number of hexadecimal characters of a short content hash
+const ShortHashLength = 12
+
+// ContentHash computes a reproducible SHA-256 digest of the estimation content.
+// The hash is computed over a canonical serialization where map keys are sorted
+// and timestamps (task ones included) are pinned, so that it only changes when the
+// content does.
+func ContentHash(estimation *model.Estimation) (string, error) {
+	canonical := estimation.Clone()
+	canonical.CreatedAt = time.Time{}
+	canonical.UpdatedAt = time.Time{}
+	canonical.ApprovedAt = nil
+	for _, task := range canonical.Tasks {
+		task.CreatedAt = time.Time{}
+		task.UpdatedAt = time.Time{}
+	}
+
+	// yaml.v3 emits map keys in sorted order
+	data, err := yaml.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ShortContentHash returns the abbreviated form of ContentHash
+func ShortContentHash(estimation *model.Estimation) (string, error) {
+	hash, err := ContentHash(estimation)
+	if err != nil {
+		return "", err
+	}
+	return hash[:ShortHashLength], nil
+}
@@ -1,31 +1,65 @@
 package format
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"math"
+	"time"
 
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/bornholm/guesstimate/internal/version"
 )
 
 // JSONFormatter formats estimations as JSON with calculated values
 type JSONFormatter struct {
-	config *model.Config
+	config      *model.Config
+	generatedAt time.Time
+	warnings    []string
 }
 
-// NewJSONFormatter creates a new JSON formatter
+// NewJSONFormatter creates a new JSON formatter, stamping its outputs with the current time
 func NewJSONFormatter(config *model.Config) *JSONFormatter {
-	return &JSONFormatter{config: config}
+	return &JSONFormatter{config: config, generatedAt: time.Now()}
+}
+
+// WithGeneratedAt sets the generation time of the outputs, omitted if zero so that the
+// outputs of an unchanged estimation are reproducible
+func (f *JSONFormatter) WithGeneratedAt(generatedAt time.Time) *JSONFormatter {
+	f.generatedAt = generatedAt
+	return f
+}
+
+// WithWarnings sets the warnings reported in the outputs, e.g. the inconsistencies
+// between the files of a combined estimation
+func (f *JSONFormatter) WithWarnings(warnings []string) *JSONFormatter {
+	f.warnings = warnings
+	return f
 }
 
 // Output represents the complete estimation output with calculated values
 type Output struct {
+	// Tool that produced the output
+	Generator GeneratorOutput `json:"generator"`
+
 	// Project information
-	ID          string `json:"id"`
-	Label       string `json:"label"`
-	Description string `json:"description,omitempty"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	ID          string   `json:"id"`
+	Label       string   `json:"label"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	TeamSize    int      `json:"teamSize,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Locked      bool     `json:"locked,omitempty"`
+	ApprovedBy  string   `json:"approvedBy,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	ContentHash string   `json:"contentHash,omitempty"`
+
+	// Warnings about the estimation, e.g. conflicting rates between combined files
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 
 	// Tasks
 	Tasks []TaskOutput `json:"tasks"`
@@ -36,19 +70,36 @@ type Output struct {
 	// Category distribution
 	CategoryDistribution []CategoryDistributionOutput `json:"categoryDistribution"`
 
-	// Cost estimation
-	Costs CostOutput `json:"costs"`
+	// Cost estimation, omitted when the costs are not shown
+	Costs *CostOutput `json:"costs,omitempty"`
+}
+
+// GeneratorOutput describes the tool that produced an output
+type GeneratorOutput struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	GeneratedAt string `json:"generatedAt,omitempty"`
 }
 
 // TaskOutput represents a task with calculated values
 type TaskOutput struct {
-	ID            string               `json:"id"`
-	Label         string               `json:"label"`
-	Description   string               `json:"description,omitempty"`
-	Category      string               `json:"category"`
-	CategoryLabel string               `json:"categoryLabel"`
-	Estimations   EstimationOutput     `json:"estimations"`
-	Calculated    TaskCalculatedOutput `json:"calculated"`
+	ID            string           `json:"id"`
+	Label         string           `json:"label"`
+	Description   string           `json:"description,omitempty"`
+	Category      string           `json:"category"`
+	CategoryLabel string           `json:"categoryLabel"`
+	Assignee      string           `json:"assignee,omitempty"`
+	Estimations   EstimationOutput `json:"estimations"`
+	// DisplayEstimations are the estimations as displayed by the reports, rounded
+	// according to the rounding configuration
+	DisplayEstimations EstimationOutput     `json:"displayEstimations"`
+	MaxEstimate        *float64             `json:"maxEstimate,omitempty"`
+	Confidence         string               `json:"confidence,omitempty"`
+	FixedCost          float64              `json:"fixedCost,omitempty"`
+	Tags               []string             `json:"tags,omitempty"`
+	CreatedAt          string               `json:"createdAt,omitempty"`
+	UpdatedAt          string               `json:"updatedAt,omitempty"`
+	Calculated         TaskCalculatedOutput `json:"calculated"`
 }
 
 // EstimationOutput represents the three-point estimates
@@ -60,18 +111,30 @@ type EstimationOutput struct {
 
 // TaskCalculatedOutput represents calculated values for a task
 type TaskCalculatedOutput struct {
-	WeightedMean      float64 `json:"weightedMean"`
-	StandardDeviation float64 `json:"standardDeviation"`
+	WeightedMean      float64         `json:"weightedMean"`
+	StandardDeviation float64         `json:"standardDeviation"`
+	Capped            bool            `json:"capped,omitempty"`
+	Cost              *TaskCostOutput `json:"cost,omitempty"`
+}
+
+// TaskCostOutput represents the share of a task in the min and max costs of its category,
+// at the cost confidence level
+type TaskCostOutput struct {
+	Max CostDetail `json:"max"`
+	Min CostDetail `json:"min"`
 }
 
 // StatisticsOutput represents project-level statistics
 type StatisticsOutput struct {
-	TaskCount         int              `json:"taskCount"`
-	WeightedMean      float64          `json:"weightedMean"`
-	StandardDeviation float64          `json:"standardDeviation"`
-	Confidence68      ConfidenceOutput `json:"confidence68"`
-	Confidence90      ConfidenceOutput `json:"confidence90"`
-	Confidence997     ConfidenceOutput `json:"confidence997"`
+	Empty             bool               `json:"empty,omitempty"`
+	TaskCount         int                `json:"taskCount"`
+	WeightedMean      float64            `json:"weightedMean"`
+	StandardDeviation float64            `json:"standardDeviation"`
+	Correlation       float64            `json:"correlationCoefficient"`
+	Confidence68      ConfidenceOutput   `json:"confidence68"`
+	Confidence90      ConfidenceOutput   `json:"confidence90"`
+	Confidence997     ConfidenceOutput   `json:"confidence997"`
+	ConfidenceLevels  []ConfidenceOutput `json:"confidenceLevels"`
 }
 
 // ConfidenceOutput represents a confidence interval
@@ -93,11 +156,32 @@ type CategoryDistributionOutput struct {
 
 // CostOutput represents cost estimation
 type CostOutput struct {
+	Confidence string                `json:"confidence"`
 	Currency   string                `json:"currency"`
 	TimeUnit   string                `json:"timeUnit"`
 	Max        CostDetail            `json:"max"`
 	Min        CostDetail            `json:"min"`
 	ByCategory map[string]CostDetail `json:"byCategory"`
+	FixedCost  float64               `json:"fixedCost,omitempty"`
+
+	Adjustments *CostAdjustmentsOutput `json:"adjustments,omitempty"`
+}
+
+// CostAdjustmentsOutput represents the markup and the discount applied to the costs,
+// the markup first, as percentages
+type CostAdjustmentsOutput struct {
+	Markup   float64            `json:"markup"`
+	Discount float64            `json:"discount"`
+	Max      AdjustedCostOutput `json:"max"`
+	Min      AdjustedCostOutput `json:"min"`
+}
+
+// AdjustedCostOutput represents a cost from its subtotal to its total
+type AdjustedCostOutput struct {
+	Subtotal float64 `json:"subtotal"`
+	Markup   float64 `json:"markup"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
 }
 
 // CostDetail represents detailed cost information
@@ -116,103 +200,290 @@ func (f *JSONFormatter) Format(estimation *model.Estimation) (string, error) {
 	return string(data) + "\n", nil
 }
 
+// Stream writes the estimation as JSON to w, encoding the tasks one at a time instead of
+// building them all in memory first. The output is identical to the one of Format.
+func (f *JSONFormatter) Stream(estimation *model.Estimation, w io.Writer) error {
+	output, distribution, costs := f.buildOutput(estimation, false)
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The tasks of the output being left empty, they are encoded in their place. Being a
+	// top-level field, its key is the only one indented by two spaces on a new line.
+	head, tail, ok := bytes.Cut(data, []byte(jsonTasksField+"[]"))
+	if !ok {
+		return errors.New("no tasks field in the JSON output")
+	}
+
+	stream := &jsonStream{w: bufio.NewWriter(w)}
+	stream.write(head)
+	stream.writeString(jsonTasksField)
+
+	tasks := estimation.GetOrderedTasks()
+	if len(tasks) == 0 {
+		stream.writeString("[]")
+	} else {
+		stream.writeString("[")
+		for j, task := range tasks {
+			if j > 0 {
+				stream.writeString(",")
+			}
+			stream.writeString("\n    ")
+			stream.encode(f.buildTaskOutput(task, distribution, costs), "    ")
+		}
+		stream.writeString("\n  ]")
+	}
+
+	stream.write(tail)
+	stream.writeString("\n")
+
+	if stream.err != nil {
+		return stream.err
+	}
+	return stream.w.Flush()
+}
+
+// jsonTasksField is the key of the tasks in the indented JSON output
+const jsonTasksField = "\n  \"tasks\": "
+
+// jsonStream writes indented JSON values to a buffered writer, keeping the first error
+// and skipping the writes following it
+type jsonStream struct {
+	w   *bufio.Writer
+	err error
+}
+
+// write writes raw JSON data
+func (s *jsonStream) write(data []byte) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.Write(data)
+}
+
+// writeString writes raw JSON text
+func (s *jsonStream) writeString(text string) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.WriteString(text)
+}
+
+// encode writes a value indented as if nested at the given prefix
+func (s *jsonStream) encode(v any, prefix string) {
+	if s.err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(v, prefix, "  ")
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.write(data)
+}
+
 // BuildOutput builds the output structure
 func (f *JSONFormatter) BuildOutput(estimation *model.Estimation) *Output {
-	projectEst := stats.CalculateProjectEstimation(estimation)
+	output, _, _ := f.buildOutput(estimation, true)
+	return output
+}
+
+// buildTaskOutput builds the output of a single task, its costs being distributed from
+// the given category costs
+func (f *JSONFormatter) buildTaskOutput(task *model.Task, distribution []stats.CategoryDistribution, costs stats.MinMaxCost) TaskOutput {
+	roundUp := f.config.RoundUpParts()
+	cat := f.config.GetTaskCategory(task.Category)
+	display := f.config.DisplayEstimations(task.Estimations)
+
+	var cost *TaskCostOutput
+	if f.config.HasCosts() {
+		minCost, maxCost := stats.CalculateTaskMinMaxCost(task, distribution, costs)
+		cost = &TaskCostOutput{
+			Max: CostDetail{Time: roundFloat(maxCost.Time, roundUp), Cost: roundFloat(maxCost.Cost, false)},
+			Min: CostDetail{Time: roundFloat(minCost.Time, roundUp), Cost: roundFloat(minCost.Cost, false)},
+		}
+	}
+
+	return TaskOutput{
+		ID:            string(task.ID),
+		Label:         task.Label,
+		Description:   task.Description,
+		Category:      task.Category,
+		CategoryLabel: cat.Label,
+		Assignee:      task.Assignee,
+		Estimations: EstimationOutput{
+			Optimistic:  task.Estimations.Optimistic,
+			Likely:      task.Estimations.Likely,
+			Pessimistic: task.Estimations.Pessimistic,
+		},
+		DisplayEstimations: EstimationOutput{
+			Optimistic:  display.Optimistic,
+			Likely:      display.Likely,
+			Pessimistic: display.Pessimistic,
+		},
+		MaxEstimate: task.MaxEstimate,
+		Confidence:  task.Confidence,
+		FixedCost:   task.FixedCost,
+		Tags:        task.Tags,
+		CreatedAt:   formatTimestamp(task.CreatedAt),
+		UpdatedAt:   formatTimestamp(task.UpdatedAt),
+		Calculated: TaskCalculatedOutput{
+			WeightedMean:      roundFloat(task.WeightedMean(), roundUp),
+			StandardDeviation: roundFloat(task.StandardDeviationWith(f.config.GetEstimationModel()), roundUp),
+			Capped:            task.IsCapped(),
+			Cost:              cost,
+		},
+	}
+}
+
+// buildOutput builds the output structure, leaving the tasks empty unless withTasks is set.
+// The category distribution and the costs the task outputs are built from are returned too.
+func (f *JSONFormatter) buildOutput(estimation *model.Estimation, withTasks bool) (*Output, []stats.CategoryDistribution, stats.MinMaxCost) {
+	projectEst := stats.CalculateProjectEstimationFor(estimation, f.config)
 	distribution := stats.CalculateCategoryDistribution(estimation, f.config)
-	costs := stats.CalculateMinMaxCosts(estimation, f.config, stats.Confidence997)
 	roundUp := f.config.RoundUpEstimations
+	roundUpParts := f.config.RoundUpParts()
+
+	// Costs are only computed when shown
+	var costs stats.MinMaxCost
+	var costOutput *CostOutput
+	if f.config.HasCosts() {
+		costConfidence := stats.CostConfidenceLevel(f.config)
+		costs = stats.CalculateMinMaxCostsFrom(projectEst, distribution, stats.CalculateFixedCost(estimation), f.config, costConfidence)
+		costOutput = f.buildCostOutput(costs, costConfidence)
+	}
 
 	// Build tasks output
-	tasks := make([]TaskOutput, 0, len(estimation.Tasks))
-	for _, task := range estimation.GetOrderedTasks() {
-		cat := f.config.GetTaskCategory(task.Category)
-		tasks = append(tasks, TaskOutput{
-			ID:            string(task.ID),
-			Label:         task.Label,
-			Description:   task.Description,
-			Category:      task.Category,
-			CategoryLabel: cat.Label,
-			Estimations: EstimationOutput{
-				Optimistic:  task.Estimations.Optimistic,
-				Likely:      task.Estimations.Likely,
-				Pessimistic: task.Estimations.Pessimistic,
-			},
-			Calculated: TaskCalculatedOutput{
-				WeightedMean:      roundFloat(task.WeightedMean(), roundUp),
-				StandardDeviation: roundFloat(task.StandardDeviation(), roundUp),
-			},
-		})
+	tasks := make([]TaskOutput, 0)
+	if withTasks {
+		tasks = make([]TaskOutput, 0, len(estimation.Tasks))
+		for _, task := range estimation.GetOrderedTasks() {
+			tasks = append(tasks, f.buildTaskOutput(task, distribution, costs))
+		}
 	}
 
 	// Build category distribution
 	catDist := make([]CategoryDistributionOutput, 0, len(distribution))
-	for _, dist := range distribution {
+	for _, dist := range stats.SortByShare(distribution) {
 		catDist = append(catDist, CategoryDistributionOutput{
 			CategoryID:    dist.CategoryID,
 			CategoryLabel: dist.CategoryLabel,
-			Time:          roundFloat(dist.Time, roundUp),
+			Time:          roundFloat(dist.Time, roundUpParts),
 			Percentage:    dist.Percentage,
 		})
 	}
 
-	// Build costs by category
-	costsByCategory := make(map[string]CostDetail)
-	for catID, catCost := range costs.Max.Details {
-		costsByCategory[catID] = CostDetail{
-			Time: roundFloat(catCost.Time, roundUp),
-			Cost: roundFloat(catCost.Cost, false),
-		}
+	// A hash failure only omits the field from the output
+	contentHash, _ := ShortContentHash(estimation)
+
+	// Build all configured confidence intervals
+	levels := stats.GetConfidenceLevels(f.config)
+	confidenceLevels := make([]ConfidenceOutput, 0, len(levels))
+	for _, cl := range levels {
+		confidenceLevels = append(confidenceLevels, buildConfidenceOutput(projectEst, cl, roundUp))
 	}
 
-	return &Output{
+	output := &Output{
+		Generator: GeneratorOutput{
+			Name:        version.Name,
+			Version:     version.Version,
+			GeneratedAt: formatTimestamp(f.generatedAt),
+		},
 		ID:          string(estimation.ID),
 		Label:       estimation.Label,
 		Description: estimation.Description,
+		Owner:       estimation.Owner,
+		TeamSize:    estimation.TeamSize,
+		Tags:        estimation.Tags,
+		Locked:      estimation.Locked,
+		ApprovedBy:  estimation.ApprovedBy,
 		CreatedAt:   estimation.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   estimation.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ContentHash: contentHash,
+		Warnings:    f.warnings,
 		Tasks:       tasks,
 		Statistics: StatisticsOutput{
+			Empty:             projectEst.IsEmpty(),
 			TaskCount:         len(estimation.Tasks),
 			WeightedMean:      roundFloat(projectEst.WeightedMean, roundUp),
 			StandardDeviation: roundFloat(projectEst.StandardDeviation, roundUp),
-			Confidence68: ConfidenceOutput{
-				Level:     "68%",
-				Mean:      roundFloat(projectEst.WeightedMean, roundUp),
-				Deviation: roundFloat(projectEst.StandardDeviation, roundUp),
-				Min:       roundFloat(projectEst.WeightedMean-projectEst.StandardDeviation, roundUp),
-				Max:       roundFloat(projectEst.WeightedMean+projectEst.StandardDeviation, roundUp),
-			},
-			Confidence90: ConfidenceOutput{
-				Level:     "90%",
-				Mean:      roundFloat(projectEst.WeightedMean, roundUp),
-				Deviation: roundFloat(projectEst.StandardDeviation*1.645, roundUp),
-				Min:       roundFloat(projectEst.WeightedMean-projectEst.StandardDeviation*1.645, roundUp),
-				Max:       roundFloat(projectEst.WeightedMean+projectEst.StandardDeviation*1.645, roundUp),
-			},
-			Confidence997: ConfidenceOutput{
-				Level:     "99.7%",
-				Mean:      roundFloat(projectEst.WeightedMean, roundUp),
-				Deviation: roundFloat(projectEst.StandardDeviation*3, roundUp),
-				Min:       roundFloat(projectEst.WeightedMean-projectEst.StandardDeviation*3, roundUp),
-				Max:       roundFloat(projectEst.WeightedMean+projectEst.StandardDeviation*3, roundUp),
-			},
+			Correlation:       f.config.GetCorrelationCoefficient(),
+			Confidence68:      buildConfidenceOutput(projectEst, stats.ResolveConfidenceLevel(f.config, stats.Confidence68), roundUp),
+			Confidence90:      buildConfidenceOutput(projectEst, stats.ResolveConfidenceLevel(f.config, stats.Confidence90), roundUp),
+			Confidence997:     buildConfidenceOutput(projectEst, stats.ResolveConfidenceLevel(f.config, stats.Confidence997), roundUp),
+			ConfidenceLevels:  confidenceLevels,
 		},
 		CategoryDistribution: catDist,
-		Costs: CostOutput{
-			Currency:   f.config.Currency,
-			TimeUnit:   f.config.TimeUnit.Acronym,
-			Max:        CostDetail{Time: roundFloat(costs.Max.TotalTime, roundUp), Cost: roundFloat(costs.Max.TotalCost, false)},
-			Min:        CostDetail{Time: roundFloat(costs.Min.TotalTime, roundUp), Cost: roundFloat(costs.Min.TotalCost, false)},
-			ByCategory: costsByCategory,
-		},
+		Costs:                costOutput,
 	}
+
+	return output, distribution, costs
 }
 
-// roundFloat rounds the value if roundUp is true, otherwise returns the value
+// buildCostOutput builds the output of the min and max costs at the given confidence level
+func (f *JSONFormatter) buildCostOutput(costs stats.MinMaxCost, confidence stats.ConfidenceLevel) *CostOutput {
+	roundUp := f.config.RoundUpEstimations
+	roundUpParts := f.config.RoundUpParts()
+
+	costsByCategory := make(map[string]CostDetail)
+	for catID, catCost := range costs.Max.Details {
+		costsByCategory[catID] = CostDetail{
+			Time: roundFloat(catCost.Time, roundUpParts),
+			Cost: roundFloat(catCost.Cost, false),
+		}
+	}
+
+	return &CostOutput{
+		Confidence:  confidence.Name,
+		Currency:    f.config.Currency,
+		TimeUnit:    f.config.TimeUnit.Acronym,
+		Max:         CostDetail{Time: roundFloat(costs.Max.TotalTime, roundUp), Cost: roundFloat(costs.Max.TotalCost, false)},
+		Min:         CostDetail{Time: roundFloat(costs.Min.TotalTime, roundUp), Cost: roundFloat(costs.Min.TotalCost, false)},
+		ByCategory:  costsByCategory,
+		FixedCost:   costs.Max.FixedCost,
+		Adjustments: f.buildCostAdjustments(costs),
+	}
+}
+
+// buildCostAdjustments builds the adjustments of the min and max costs, if any applies
+func (f *JSONFormatter) buildCostAdjustments(costs stats.MinMaxCost) *CostAdjustmentsOutput {
+	if !f.config.HasCostAdjustments() {
+		return nil
+	}
+	return &CostAdjustmentsOutput{
+		Markup:   f.config.Markup,
+		Discount: f.config.Discount,
+		Max:      AdjustedCostOutput(stats.AdjustCost(costs.Max.TotalCost, f.config)),
+		Min:      AdjustedCostOutput(stats.AdjustCost(costs.Min.TotalCost, f.config)),
+	}
+}
+
+// buildConfidenceOutput builds the confidence interval output for the given level
+func buildConfidenceOutput(projectEst stats.EstimationResult, cl stats.ConfidenceLevel, roundUp bool) ConfidenceOutput {
+	return ConfidenceOutput{
+		Level:     cl.Name,
+		Mean:      roundFloat(projectEst.WeightedMean, roundUp),
+		Deviation: roundFloat(projectEst.StandardDeviation*cl.Multiplier, roundUp),
+		Min:       roundFloat(projectEst.WeightedMean-projectEst.StandardDeviation*cl.Multiplier, roundUp),
+		Max:       roundFloat(projectEst.WeightedMean+projectEst.StandardDeviation*cl.Multiplier, roundUp),
+	}
+}
+
+// roundFloat rounds the value if roundUp is true, otherwise returns the value. It only
+// applies to the output, the stored estimates are never rounded.
 func roundFloat(value float64, roundUp bool) float64 {
 	if roundUp {
 		return math.Ceil(value)
 	}
 	return value
 }
+
+// formatTimestamp formats a timestamp as UTC, leaving untracked (zero) ones empty
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
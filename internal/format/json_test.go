@@ -0,0 +1,155 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+func TestBuildOutputEmptyEstimation(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []*model.Task
+	}{
+		{name: "no tasks"},
+		{name: "all-zero estimates", tasks: []*model.Task{model.NewTask("A", "development"), model.NewTask("B", "testing")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimation := model.NewEstimation(tt.name)
+			for _, task := range tt.tasks {
+				estimation.AddTask(task)
+			}
+
+			output := NewJSONFormatter(model.DefaultConfig()).BuildOutput(estimation)
+
+			// NaN values can't be marshalled
+			if _, err := json.Marshal(output); err != nil {
+				t.Fatalf("failed to marshal output: %v", err)
+			}
+
+			statistics := output.Statistics
+			if !statistics.Empty {
+				t.Errorf("statistics.empty = false, want true")
+			}
+			if statistics.TaskCount != len(tt.tasks) {
+				t.Errorf("statistics.taskCount = %d, want %d", statistics.TaskCount, len(tt.tasks))
+			}
+			if statistics.WeightedMean != 0 || statistics.StandardDeviation != 0 {
+				t.Errorf("statistics = %v ± %v, want 0 ± 0", statistics.WeightedMean, statistics.StandardDeviation)
+			}
+			if output.Costs == nil || output.Costs.Max.Cost != 0 || output.Costs.Min.Cost != 0 {
+				t.Errorf("costs = %+v, want zero costs", output.Costs)
+			}
+		})
+	}
+}
+
+func TestBuildOutputGeneratedAt(t *testing.T) {
+	estimation := model.NewEstimation("Project")
+
+	generatedAt := time.Date(2026, 3, 14, 9, 26, 53, 0, time.FixedZone("CET", 3600))
+	output := NewJSONFormatter(model.DefaultConfig()).WithGeneratedAt(generatedAt).BuildOutput(estimation)
+	if want := "2026-03-14T08:26:53Z"; output.Generator.GeneratedAt != want {
+		t.Errorf("generator.generatedAt = %q, want %q", output.Generator.GeneratedAt, want)
+	}
+
+	formatter := NewJSONFormatter(model.DefaultConfig()).WithGeneratedAt(time.Time{})
+	first, err := formatter.Format(estimation)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if strings.Contains(first, "generatedAt") {
+		t.Errorf("Format() without generation time = %s, want no generatedAt field", first)
+	}
+	second, err := formatter.Format(estimation)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if first != second {
+		t.Errorf("Format() is not reproducible:\n%s\n%s", first, second)
+	}
+}
+
+func TestStreamMatchesFormat(t *testing.T) {
+	generatedAt := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+
+	newEstimation := func(tasks int) *model.Estimation {
+		estimation := model.NewEstimation("Project <Alpha> & co")
+		estimation.Description = "Described"
+		estimation.Tags = []string{"q1"}
+		for i := range tasks {
+			task := model.NewTask(fmt.Sprintf("Task \"%d\" é", i), []string{"development", "testing", "design"}[i%3])
+			task.SetEstimations(float64(i%5+1), float64(i%5+2), float64(i%5+4), model.DefaultAutoEstimationMultiplier)
+			task.Tags = []string{"backend"}
+			estimation.AddTask(task)
+		}
+		return estimation
+	}
+
+	noCost := model.DefaultConfig()
+	showCost := false
+	noCost.ShowCost = &showCost
+
+	tests := []struct {
+		name       string
+		estimation *model.Estimation
+		config     *model.Config
+		warnings   []string
+	}{
+		{name: "no tasks", estimation: newEstimation(0), config: model.DefaultConfig()},
+		{name: "tasks", estimation: newEstimation(50), config: model.DefaultConfig()},
+		{name: "many tasks", estimation: newEstimation(2000), config: model.DefaultConfig()},
+		{name: "without costs", estimation: newEstimation(10), config: noCost},
+		{name: "warnings", estimation: newEstimation(10), config: model.DefaultConfig(), warnings: []string{"b.yml uses a 10% markup instead of 0%"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewJSONFormatter(tt.config).WithGeneratedAt(generatedAt).WithWarnings(tt.warnings)
+
+			want, err := formatter.Format(tt.estimation)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := formatter.Stream(tt.estimation, &buf); err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+
+			if got := buf.String(); got != want {
+				t.Errorf("Stream() differs from Format():\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+var errWriteFailed = errors.New("write failed")
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func TestStreamReportsWriteErrors(t *testing.T) {
+	estimation := model.NewEstimation("Project")
+	for i := range 500 {
+		task := model.NewTask(fmt.Sprintf("Task %d", i), "development")
+		task.SetEstimations(1, 2, 4, model.DefaultAutoEstimationMultiplier)
+		estimation.AddTask(task)
+	}
+
+	if err := NewJSONFormatter(model.DefaultConfig()).Stream(estimation, failingWriter{}); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Stream() error = %v, want %v", err, errWriteFailed)
+	}
+}
@@ -2,26 +2,54 @@ package format
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/bornholm/guesstimate/internal/i18n"
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/stats"
 )
 
 // MarkdownFormatter formats estimations as markdown
 type MarkdownFormatter struct {
-	config *model.Config
+	config        *model.Config
+	withTasks     bool
+	allCategories bool
+	warnings      []string
 }
 
 // NewMarkdownFormatter creates a new markdown formatter
 func NewMarkdownFormatter(config *model.Config) *MarkdownFormatter {
-	return &MarkdownFormatter{config: config}
+	return &MarkdownFormatter{config: config, withTasks: true}
+}
+
+// WithTasks sets whether the report includes the tasks table (the default), leaving only
+// the summary, cost and category sections when disabled
+func (f *MarkdownFormatter) WithTasks(withTasks bool) *MarkdownFormatter {
+	f.withTasks = withTasks
+	return f
+}
+
+// WithAllCategories sets whether the category tables list the configured categories no
+// task uses (0%), hidden by default
+func (f *MarkdownFormatter) WithAllCategories(allCategories bool) *MarkdownFormatter {
+	f.allCategories = allCategories
+	return f
+}
+
+// WithWarnings sets the warnings listed at the top of the report, see
+// JSONFormatter.WithWarnings
+func (f *MarkdownFormatter) WithWarnings(warnings []string) *MarkdownFormatter {
+	f.warnings = warnings
+	return f
 }
 
 // Format formats an estimation as markdown
 func (f *MarkdownFormatter) Format(estimation *model.Estimation) string {
 	var sb strings.Builder
+	tr := i18n.For(f.config.Language)
 
 	// Title
 	sb.WriteString(fmt.Sprintf("# %s\n\n", estimation.Label))
@@ -31,93 +59,180 @@ func (f *MarkdownFormatter) Format(estimation *model.Estimation) string {
 		sb.WriteString(fmt.Sprintf("> %s\n\n", estimation.Description))
 	}
 
+	// Owner and team
+	if estimation.Owner != "" {
+		sb.WriteString(fmt.Sprintf("**%s:** %s\n\n", tr.T("Owner"), estimation.Owner))
+	}
+	if estimation.TeamSize > 0 {
+		sb.WriteString(fmt.Sprintf("**%s:** %d\n\n", tr.T("Team size"), estimation.TeamSize))
+	}
+	if len(estimation.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("**%s:** %s\n\n", tr.T("Tags"), strings.Join(estimation.Tags, ", ")))
+	}
+
+	// Warnings
+	if len(f.warnings) > 0 {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", tr.T("Warnings")))
+		for _, warning := range f.warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Summary
-	sb.WriteString("## Summary\n\n")
-	sb.WriteString("| Confidence | Estimation |\n")
-	sb.WriteString("|------------|------------|\n")
+	sb.WriteString(fmt.Sprintf("## %s\n\n", tr.T("Summary")))
 
-	projectEst := stats.CalculateProjectEstimation(estimation)
+	projectEst := stats.CalculateProjectEstimationFor(estimation, f.config)
+	distribution := stats.CalculateCategoryDistribution(estimation, f.config)
 	roundUp := f.config.RoundUpEstimations
+	roundUpParts := f.config.RoundUpParts()
+
+	if projectEst.IsEmpty() {
+		sb.WriteString(fmt.Sprintf("%s.\n\n", tr.T("No estimates yet")))
+	} else {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", tr.T("Confidence"), tr.T("Estimation")))
+		sb.WriteString("|------------|------------|\n")
 
-	for _, cl := range []stats.ConfidenceLevel{stats.Confidence997, stats.Confidence90, stats.Confidence68} {
-		e := projectEst.WeightedMean
-		sd := projectEst.StandardDeviation * cl.Multiplier
+		for _, cl := range stats.GetConfidenceLevels(f.config) {
+			e := projectEst.WeightedMean
+			sd := projectEst.StandardDeviation * cl.Multiplier
 
-		eStr := formatFloat(e, roundUp)
-		sdStr := formatFloat(sd, roundUp)
+			eStr := formatFloat(e, roundUp)
+			sdStr := formatFloat(sd, roundUp)
 
-		sb.WriteString(fmt.Sprintf("| >= %s | %s ± %s %s |\n", cl.Name, eStr, sdStr, f.config.TimeUnit.Acronym))
+			sb.WriteString(fmt.Sprintf("| >= %s | %s ± %s %s |\n", cl.Name, eStr, sdStr, f.config.TimeUnit.Acronym))
+		}
+		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
 
 	// Financial Preview
-	sb.WriteString("## Financial Preview\n\n")
-	costs := stats.CalculateMinMaxCosts(estimation, f.config, stats.Confidence997)
+	if f.config.HasCosts() {
+		f.writeFinancialPreview(&sb, tr, estimation, projectEst, distribution)
+	} else if f.config.GetShowCost() {
+		sb.WriteString(fmt.Sprintf("*%s*\n\n", tr.T("Costs are unavailable for story points.")))
+	}
+
+	// Tasks
+	if f.withTasks {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", tr.T("Tasks")))
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			tr.T("Task"), tr.T("Category"), tr.T("Optimistic"), tr.T("Likely"), tr.T("Pessimistic"), tr.T("Mean"), tr.T("SD")))
+		sb.WriteString("|------|----------|------------|--------|-------------|------|----|\n")
+
+		for _, task := range estimation.GetOrderedTasks() {
+			cat := f.config.GetTaskCategory(task.Category)
+			mean := task.WeightedMean()
+			sd := task.StandardDeviationWith(f.config.GetEstimationModel())
+			estimations := f.config.DisplayEstimations(task.Estimations)
+
+			label := task.Label
+			if task.IsCapped() {
+				label += " " + tr.Sprintf("(capped at %s)", formatFloat(*task.MaxEstimate, false))
+			}
+
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				label,
+				cat.Label,
+				formatFloat(estimations.Optimistic, false),
+				formatFloat(estimations.Likely, false),
+				formatFloat(estimations.Pessimistic, false),
+				formatFloat(mean, roundUpParts),
+				formatFloat(sd, roundUpParts),
+			))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Category Distribution
+	sb.WriteString(fmt.Sprintf("## %s\n\n", tr.T("Category Distribution")))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", tr.T("Category"), tr.T("Percentage")))
+	sb.WriteString("|----------|------------|\n")
+
+	for _, dist := range stats.SortByShare(distribution) {
+		if dist.Percentage == 0 && !f.allCategories {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %.0f%% |\n", dist.CategoryLabel, dist.Percentage))
+	}
+	sb.WriteString("\n")
+
+	// Footer
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("*%s*\n", tr.Sprintf("Generated by Guesstimate CLI on %s", time.Now().Format("2006-01-02 15:04:05"))))
+
+	return sb.String()
+}
 
-	sb.WriteString("| Type | Time | Cost |\n")
+// writeFinancialPreview writes the min and max costs, their adjustments and the cost by
+// category of the estimation
+func (f *MarkdownFormatter) writeFinancialPreview(sb *strings.Builder, tr *i18n.Translator, estimation *model.Estimation, projectEst stats.EstimationResult, distribution []stats.CategoryDistribution) {
+	roundUp := f.config.RoundUpEstimations
+	roundUpParts := f.config.RoundUpParts()
+
+	sb.WriteString(fmt.Sprintf("## %s\n\n", tr.T("Financial Preview")))
+	costConfidence := stats.CostConfidenceLevel(f.config)
+	costs := stats.CalculateMinMaxCostsFrom(projectEst, distribution, stats.CalculateFixedCost(estimation), f.config, costConfidence)
+	sb.WriteString(tr.Sprintf("Based on the %s confidence interval.", costConfidence.Name) + "\n\n")
+
+	sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tr.T("Type"), tr.T("Time"), tr.T("Cost")))
 	sb.WriteString("|------|------|------|\n")
-	sb.WriteString(fmt.Sprintf("| Maximum | %s %s | %s %s |\n",
+	sb.WriteString(fmt.Sprintf("| %s | %s %s | %s %s |\n",
+		tr.T("Maximum"),
 		formatFloat(costs.Max.TotalTime, roundUp), f.config.TimeUnit.Acronym,
 		formatFloat(costs.Max.TotalCost, false), f.config.Currency))
-	sb.WriteString(fmt.Sprintf("| Minimum | %s %s | %s %s |\n",
+	sb.WriteString(fmt.Sprintf("| %s | %s %s | %s %s |\n",
+		tr.T("Minimum"),
 		formatFloat(costs.Min.TotalTime, roundUp), f.config.TimeUnit.Acronym,
 		formatFloat(costs.Min.TotalCost, false), f.config.Currency))
 	sb.WriteString("\n")
 
+	// Cost Adjustments
+	if f.config.HasCostAdjustments() {
+		maxCost := stats.AdjustCost(costs.Max.TotalCost, f.config)
+		minCost := stats.AdjustCost(costs.Min.TotalCost, f.config)
+		writeLine := func(label string, maxAmount, minAmount float64) {
+			sb.WriteString(fmt.Sprintf("| %s | %s %s | %s %s |\n",
+				label, formatFloat(maxAmount, false), f.config.Currency, formatFloat(minAmount, false), f.config.Currency))
+		}
+
+		sb.WriteString(fmt.Sprintf("### %s\n\n", tr.T("Cost Adjustments")))
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tr.T("Type"), tr.T("Maximum"), tr.T("Minimum")))
+		sb.WriteString("|------|---------|---------|\n")
+		writeLine(tr.T("Subtotal"), maxCost.Subtotal, minCost.Subtotal)
+		if f.config.Markup != 0 {
+			writeLine(tr.Sprintf("Markup +%g%%", f.config.Markup), maxCost.Markup, minCost.Markup)
+		}
+		if f.config.Discount != 0 {
+			writeLine(tr.Sprintf("Discount -%g%%", f.config.Discount), -maxCost.Discount, -minCost.Discount)
+		}
+		writeLine(fmt.Sprintf("**%s**", tr.T("Total")), maxCost.Total, minCost.Total)
+		sb.WriteString("\n")
+	}
+
 	// Cost by Category
-	sb.WriteString("### Cost by Category\n\n")
-	sb.WriteString("| Category | Time | Cost |\n")
+	sb.WriteString(fmt.Sprintf("### %s\n\n", tr.T("Cost by Category")))
+	sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tr.T("Category"), tr.T("Time"), tr.T("Cost")))
 	sb.WriteString("|----------|------|------|\n")
 
-	for catID, catCost := range costs.Max.Details {
+	for _, catID := range slices.Sorted(maps.Keys(costs.Max.Details)) {
 		cat := f.config.GetTaskCategory(catID)
+		catCost := costs.Max.Details[catID]
+		if catCost.Time == 0 && !f.allCategories {
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("| %s | %s %s | %s %s |\n",
 			cat.Label,
-			formatFloat(catCost.Time, roundUp), f.config.TimeUnit.Acronym,
+			formatFloat(catCost.Time, roundUpParts), f.config.TimeUnit.Acronym,
 			formatFloat(catCost.Cost, false), f.config.Currency))
 	}
-	sb.WriteString("\n")
-
-	// Tasks
-	sb.WriteString("## Tasks\n\n")
-	sb.WriteString("| Task | Category | Optimistic | Likely | Pessimistic | Mean | SD |\n")
-	sb.WriteString("|------|----------|------------|--------|-------------|------|----|\n")
-
-	for _, task := range estimation.GetOrderedTasks() {
-		cat := f.config.GetTaskCategory(task.Category)
-		mean := task.WeightedMean()
-		sd := task.StandardDeviation()
-
-		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
-			task.Label,
-			cat.Label,
-			formatFloat(task.Estimations.Optimistic, false),
-			formatFloat(task.Estimations.Likely, false),
-			formatFloat(task.Estimations.Pessimistic, false),
-			formatFloat(mean, roundUp),
-			formatFloat(sd, roundUp),
-		))
-	}
-	sb.WriteString("\n")
-
-	// Category Distribution
-	sb.WriteString("## Category Distribution\n\n")
-	sb.WriteString("| Category | Percentage |\n")
-	sb.WriteString("|----------|------------|\n")
-
-	distribution := stats.CalculateCategoryDistribution(estimation, f.config)
-	for _, dist := range distribution {
-		sb.WriteString(fmt.Sprintf("| %s | %.0f%% |\n", dist.CategoryLabel, dist.Percentage))
+	if costs.Max.FixedCost > 0 {
+		sb.WriteString(fmt.Sprintf("| %s | - | %s %s |\n",
+			tr.T("Fixed costs"), formatFloat(costs.Max.FixedCost, false), f.config.Currency))
 	}
 	sb.WriteString("\n")
-
-	// Footer
-	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("*Generated by Guesstimate CLI on %s*\n", time.Now().Format("2006-01-02 15:04:05")))
-
-	return sb.String()
 }
 
+// formatFloat formats a value for display, rounded up if roundUp is true
 func formatFloat(value float64, roundUp bool) string {
 	if roundUp {
 		return fmt.Sprintf("%.0f", value)
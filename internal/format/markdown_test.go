@@ -0,0 +1,86 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+func TestStoryPointsHaveNoCosts(t *testing.T) {
+	config := model.DefaultConfig()
+	config.TimeUnit = model.TimeUnit{Label: "story point", Acronym: "sp"}
+
+	estimation := model.NewEstimation("Project")
+	task := model.NewTask("Task", "development")
+	task.SetEstimations(3, 5, 8, model.DefaultAutoEstimationMultiplier)
+	task.FixedCost = 1200
+	estimation.AddTask(task)
+
+	output := NewJSONFormatter(config).BuildOutput(estimation)
+	if output.Costs != nil {
+		t.Errorf("costs = %+v, want none for story points", output.Costs)
+	}
+	if cost := output.Tasks[0].Calculated.Cost; cost != nil {
+		t.Errorf("task cost = %+v, want none for story points", cost)
+	}
+
+	report := NewMarkdownFormatter(config).Format(estimation)
+	if strings.Contains(report, "Financial Preview") || !strings.Contains(report, "Costs are unavailable for story points.") {
+		t.Errorf("markdown report = %s, want costs reported as unavailable", report)
+	}
+}
+
+func TestMarkdownAllCategories(t *testing.T) {
+	config := model.DefaultConfig()
+
+	estimation := model.NewEstimation("Project")
+	task := model.NewTask("Task", "development")
+	task.SetEstimations(3, 5, 8, model.DefaultAutoEstimationMultiplier)
+	estimation.AddTask(task)
+
+	unusedRow := fmt.Sprintf("| %s | 0%% |", config.TaskCategories["testing"].Label)
+
+	tests := []struct {
+		name          string
+		allCategories bool
+		wantUnused    bool
+	}{
+		{name: "default", allCategories: false, wantUnused: false},
+		{name: "all categories", allCategories: true, wantUnused: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewMarkdownFormatter(config).WithAllCategories(tt.allCategories).Format(estimation)
+			if got := strings.Contains(report, unusedRow); got != tt.wantUnused {
+				t.Errorf("report lists the unused categories = %v, want %v:\n%s", got, tt.wantUnused, report)
+			}
+		})
+	}
+}
+
+func TestWarningsAreReported(t *testing.T) {
+	config := model.DefaultConfig()
+	estimation := model.NewEstimation("Project")
+	warning := "category 'design' costs 700.00 in a.yml but 999.00 in b.yml, keeping the first rate"
+
+	output := NewJSONFormatter(config).WithWarnings([]string{warning}).BuildOutput(estimation)
+	if len(output.Warnings) != 1 || output.Warnings[0] != warning {
+		t.Errorf("warnings = %v, want [%s]", output.Warnings, warning)
+	}
+
+	report := NewMarkdownFormatter(config).WithWarnings([]string{warning}).Format(estimation)
+	if !strings.Contains(report, "## Warnings\n\n- "+warning+"\n") {
+		t.Errorf("markdown report = %s, want the warning listed", report)
+	}
+
+	data, err := NewYAMLFormatter(config).Format(estimation)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if strings.Contains(data, "warnings") {
+		t.Errorf("YAML output = %s, want no warnings field", data)
+	}
+}
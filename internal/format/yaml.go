@@ -1,24 +1,40 @@
 package format
 
 import (
+	"time"
+
 	"github.com/bornholm/guesstimate/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
 // YAMLFormatter formats estimations as YAML with calculated values
 type YAMLFormatter struct {
-	config *model.Config
+	config      *model.Config
+	generatedAt time.Time
+	warnings    []string
 }
 
-// NewYAMLFormatter creates a new YAML formatter
+// NewYAMLFormatter creates a new YAML formatter, stamping its outputs with the current time
 func NewYAMLFormatter(config *model.Config) *YAMLFormatter {
-	return &YAMLFormatter{config: config}
+	return &YAMLFormatter{config: config, generatedAt: time.Now()}
+}
+
+// WithGeneratedAt sets the generation time of the outputs, see JSONFormatter.WithGeneratedAt
+func (f *YAMLFormatter) WithGeneratedAt(generatedAt time.Time) *YAMLFormatter {
+	f.generatedAt = generatedAt
+	return f
+}
+
+// WithWarnings sets the warnings reported in the outputs, see JSONFormatter.WithWarnings
+func (f *YAMLFormatter) WithWarnings(warnings []string) *YAMLFormatter {
+	f.warnings = warnings
+	return f
 }
 
 // Format formats an estimation as YAML
 func (f *YAMLFormatter) Format(estimation *model.Estimation) (string, error) {
 	// Use the same output structure as JSON formatter
-	jsonFormatter := NewJSONFormatter(f.config)
+	jsonFormatter := NewJSONFormatter(f.config).WithGeneratedAt(f.generatedAt).WithWarnings(f.warnings)
 	output := jsonFormatter.BuildOutput(estimation)
 
 	data, err := yaml.Marshal(output)
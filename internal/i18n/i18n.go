@@ -0,0 +1,159 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported languages
+const (
+	English = "en"
+	French  = "fr"
+)
+
+// DefaultLanguage is the language used when none is configured
+const DefaultLanguage = English
+
+// catalogs holds the translations of the fixed report strings, keyed by language
+// then by English message. English needs no catalog as messages are English.
+var catalogs = map[string]map[string]string{
+	French: {
+		// Summary
+		"Project: %s":                          "Projet : %s",
+		"Tasks: %d":                            "Tâches : %d",
+		"Time Estimation:":                     "Estimation du temps :",
+		"(task correlation coefficient: %.2f)": "(coefficient de corrélation des tâches : %.2f)",
+		"%s confidence:":                       "Confiance %s :",
+		"Category Repartition:":                "Répartition par catégorie :",
+		"Cost Estimation (%s confidence):":     "Estimation des coûts (confiance %s) :",
+		"Maximum:":                             "Maximum :",
+		"Minimum:":                             "Minimum :",
+		"No estimates yet":                     "Aucune estimation pour le moment",
+
+		// Story points
+		"Calendar Duration: unavailable for story points, use --as-time":                    "Durée calendaire : indisponible en points d'effort, utilisez --as-time",
+		"Calendar Duration: unavailable for story points, set a velocity and use --as-time": "Durée calendaire : indisponible en points d'effort, définissez une vélocité et utilisez --as-time",
+		"Costs are unavailable for story points.":                                           "Les coûts sont indisponibles en points d'effort.",
+		"Cost Estimation: unavailable for story points, use --as-time":                      "Estimation des coûts : indisponible en points d'effort, utilisez --as-time",
+		"Cost Estimation: unavailable for story points, set a velocity and use --as-time":   "Estimation des coûts : indisponible en points d'effort, définissez une vélocité et utilisez --as-time",
+
+		// Calendar duration
+		"Calendar Duration (%g working days per week):":             "Durée calendaire (%g jours ouvrés par semaine) :",
+		"Calendar Duration (%g working days per week, team of %d):": "Durée calendaire (%g jours ouvrés par semaine, équipe de %d) :",
+		"weeks": "semaines",
+
+		// Tag subtotals
+		"Tag Subtotals (overlapping, a task counts in each of its tags):": "Sous-totaux par étiquette (non exclusifs, une tâche compte dans chacune de ses étiquettes) :",
+		"%d tasks": "%d tâches",
+
+		// Fixed costs
+		"Including %.2f %s of fixed costs": "Dont %.2f %s de coûts fixes",
+		"Fixed costs":                      "Coûts fixes",
+
+		// Cost adjustments
+		"Cost Adjustments:": "Ajustements du coût :",
+		"Cost Adjustments":  "Ajustements du coût",
+		"Subtotal":          "Sous-total",
+		"Markup +%g%%":      "Majoration +%g %%",
+		"Discount -%g%%":    "Remise -%g %%",
+		"Total":             "Total",
+
+		// Scenarios
+		"Scenario: %s (mean %.2f %s, estimated %.2f %s)": "Scénario : %s (moyenne %.2f %s, estimée %.2f %s)",
+
+		// Baseline comparison
+		"Baseline Comparison (%s):": "Comparaison avec la référence (%s) :",
+		"Mean:":                     "Moyenne :",
+		"99.7% cost:":               "Coût à 99,7 % :",
+		"[grew beyond %g%%]":        "[hausse au-delà de %g %%]",
+
+		// Explained three-point math
+		"Calculation:": "Calcul :",
+		"Project":      "Projet",
+
+		// Markdown report
+		"Owner":                                "Responsable",
+		"Team size":                            "Taille de l'équipe",
+		"Tags":                                 "Étiquettes",
+		"Warnings":                             "Avertissements",
+		"Summary":                              "Synthèse",
+		"Confidence":                           "Confiance",
+		"Estimation":                           "Estimation",
+		"Financial Preview":                    "Aperçu financier",
+		"Based on the %s confidence interval.": "Basé sur l'intervalle de confiance à %s.",
+		"Type":                                 "Type",
+		"Time":                                 "Temps",
+		"Cost":                                 "Coût",
+		"Maximum":                              "Maximum",
+		"Minimum":                              "Minimum",
+		"Cost by Category":                     "Coût par catégorie",
+		"Tasks":                                "Tâches",
+		"Task":                                 "Tâche",
+		"Category":                             "Catégorie",
+		"Optimistic":                           "Optimiste",
+		"Likely":                               "Probable",
+		"Pessimistic":                          "Pessimiste",
+		"Mean":                                 "Moyenne",
+		"SD":                                   "Écart type",
+		"(capped at %s)":                       "(plafonné à %s)",
+		"Category Distribution":                "Répartition par catégorie",
+		"Percentage":                           "Pourcentage",
+		"Generated by Guesstimate CLI on %s":   "Généré par Guesstimate CLI le %s",
+	},
+}
+
+// Languages returns the supported languages
+func Languages() []string {
+	return []string{English, French}
+}
+
+// Normalize returns the supported language matching the given language tag
+// (e.g. "fr", "fr-FR" or "fr_CA.UTF-8"), or an error if it is not supported.
+// An empty tag is the default language.
+func Normalize(lang string) (string, error) {
+	if lang == "" {
+		return DefaultLanguage, nil
+	}
+
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "-_."); i >= 0 {
+		base = base[:i]
+	}
+
+	if base == English {
+		return English, nil
+	}
+	if _, ok := catalogs[base]; ok {
+		return base, nil
+	}
+
+	return "", fmt.Errorf("unsupported language '%s', expected one of: %s", lang, strings.Join(Languages(), ", "))
+}
+
+// Translator translates the fixed report strings into a language
+type Translator struct {
+	messages map[string]string
+}
+
+// For returns the translator of the given language, falling back to English
+// when the language is not supported
+func For(lang string) *Translator {
+	lang, err := Normalize(lang)
+	if err != nil {
+		lang = DefaultLanguage
+	}
+	return &Translator{messages: catalogs[lang]}
+}
+
+// T returns the translation of the given message, or the message itself when untranslated
+func (t *Translator) T(message string) string {
+	if translation, ok := t.messages[message]; ok {
+		return translation
+	}
+	return message
+}
+
+// Sprintf translates the given format then formats it with the arguments
+func (t *Translator) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(t.T(format), args...)
+}
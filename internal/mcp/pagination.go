@@ -0,0 +1,40 @@
+package mcp
+
+import "fmt"
+
+// paginate returns the bounds of the page starting at offset and containing at most
+// limit items out of total. A zero limit means all the remaining items.
+func paginate(total, offset, limit int) (int, int, error) {
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must be >= 0")
+	}
+	if limit < 0 {
+		return 0, 0, fmt.Errorf("limit must be >= 0")
+	}
+
+	start := min(offset, total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+
+	return start, end, nil
+}
+
+// paginationNote returns a note describing the returned page when it does not
+// cover every item
+func paginationNote(total, start, end int) string {
+	if start == 0 && end == total {
+		return ""
+	}
+
+	note := fmt.Sprintf("\nShowing %d-%d of %d", start+1, end, total)
+	if start >= end {
+		note = fmt.Sprintf("\nNo items at offset %d (total: %d)", start, total)
+	}
+	if remaining := total - end; remaining > 0 {
+		note += fmt.Sprintf(", %d remaining (use offset=%d to continue)", remaining, end)
+	}
+
+	return note + "\n"
+}
@@ -3,9 +3,16 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/bornholm/guesstimate/internal/format"
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/bornholm/guesstimate/internal/version"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -20,6 +27,9 @@ type Server struct {
 type ServerOptions struct {
 	RootDir string
 	Config  *model.Config
+	// MaxFileSize is the maximum size of the estimation files loaded by the server, in bytes.
+	// Zero uses store.DefaultMaxFileSize, a negative value disables the limit.
+	MaxFileSize int64
 }
 
 // NewServer creates a new MCP server for guesstimate operations
@@ -33,6 +43,9 @@ func NewServer(opts *ServerOptions) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chrooted store: %w", err)
 	}
+	if opts.MaxFileSize != 0 {
+		store.WithMaxFileSize(opts.MaxFileSize)
+	}
 
 	// Use provided config or default
 	config := opts.Config
@@ -41,8 +54,8 @@ func NewServer(opts *ServerOptions) (*Server, error) {
 	}
 
 	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "guesstimate",
-		Version: "1.0.0",
+		Name:    version.Name,
+		Version: version.Version,
 	}, nil)
 
 	s := &Server{
@@ -51,8 +64,9 @@ func NewServer(opts *ServerOptions) (*Server, error) {
 		config: config,
 	}
 
-	// Register tools
+	// Register tools and prompts
 	s.registerTools()
+	s.registerPrompts()
 
 	return s, nil
 }
@@ -77,6 +91,7 @@ func (s *Server) registerTools() {
 
 	// Task tools
 	s.registerListTasksTool()
+	s.registerSearchTasksTool()
 	s.registerAddTaskTool()
 	s.registerUpdateTaskTool()
 	s.registerRemoveTaskTool()
@@ -87,13 +102,15 @@ func (s *Server) registerTools() {
 
 // list_estimations tool
 type listEstimationsArgs struct {
-	Dir string `json:"dir,omitempty" jsonschema:"the directory to list estimations from, defaults to current directory"`
+	Dir    string `json:"dir,omitempty" jsonschema:"the directory to list estimations from, defaults to current directory"`
+	Offset int    `json:"offset,omitempty" jsonschema:"optional number of files to skip, defaults to 0"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"optional maximum number of files to return, defaults to all"`
 }
 
 func (s *Server) registerListEstimationsTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "list_estimations",
-		Description: "List all estimation files in a directory",
+		Description: "List all estimation files in a directory, sorted by name. Use offset and limit to paginate.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args listEstimationsArgs) (*mcp.CallToolResult, any, error) {
 		dir := args.Dir
 		if dir == "" {
@@ -113,10 +130,19 @@ func (s *Server) registerListEstimationsTool() {
 			}, nil, nil
 		}
 
+		// Sort by name so that pagination is stable across calls
+		sort.Strings(files)
+
+		start, end, err := paginate(len(files), args.Offset, args.Limit)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		result := "Estimation files:\n"
-		for _, f := range files {
+		for _, f := range files[start:end] {
 			result += fmt.Sprintf("- %s\n", f)
 		}
+		result += paginationNote(len(files), start, end)
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -128,31 +154,99 @@ func (s *Server) registerListEstimationsTool() {
 
 // create_estimation tool
 type createEstimationArgs struct {
-	Path        string `json:"path" jsonschema:"required,the file path for the estimation"`
-	Label       string `json:"label" jsonschema:"required,the label/name for the estimation"`
-	Description string `json:"description,omitempty" jsonschema:"optional description for the estimation"`
+	Path        string                     `json:"path" jsonschema:"required,the file path for the estimation"`
+	Label       string                     `json:"label" jsonschema:"required,the label/name for the estimation"`
+	Description string                     `json:"description,omitempty" jsonschema:"optional description for the estimation"`
+	Categories  []createCategoryArgs       `json:"categories,omitempty" jsonschema:"optional estimation-specific task categories, added to the configured ones and replacing the ones with the same ID"`
+	Tasks       []createEstimationTaskArgs `json:"tasks,omitempty" jsonschema:"optional initial tasks of the estimation"`
+}
+
+type createCategoryArgs struct {
+	ID              string  `json:"id" jsonschema:"required,the category ID referenced by the tasks"`
+	Label           string  `json:"label" jsonschema:"required,the category label"`
+	CostPerTimeUnit float64 `json:"costPerTimeUnit,omitempty" jsonschema:"optional cost per time unit of the category, defaults to 0"`
+}
+
+type createEstimationTaskArgs struct {
+	Label       string  `json:"label" jsonschema:"required,the task label"`
+	Description string  `json:"description,omitempty" jsonschema:"optional task description"`
+	Category    string  `json:"category,omitempty" jsonschema:"optional task category, defaults to the default category of the configuration merged with the given categories"`
+	Assignee    string  `json:"assignee,omitempty" jsonschema:"optional person assigned to the task"`
+	Optimistic  float64 `json:"optimistic,omitempty" jsonschema:"optional optimistic estimate, defaults to 0"`
+	Likely      float64 `json:"likely,omitempty" jsonschema:"optional likely estimate, defaults to 0"`
+	Pessimistic float64 `json:"pessimistic,omitempty" jsonschema:"optional pessimistic estimate, defaults to 0"`
+	Confidence  string  `json:"confidence,omitempty" jsonschema:"optional stability of the task requirements (high, medium or low), inflating its standard deviation"`
+	FixedCost   float64 `json:"fixedCost,omitempty" jsonschema:"optional certain cost unrelated to the effort (e.g. a license), defaults to 0"`
 }
 
 func (s *Server) registerCreateEstimationTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "create_estimation",
-		Description: "Create a new estimation file",
+		Description: "Create a new estimation file, optionally with its own categories and initial tasks saved at once. Missing estimation values of the tasks are auto-calculated as with add_task.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args createEstimationArgs) (*mcp.CallToolResult, any, error) {
-		estimation := model.NewEstimation(args.Label)
-		estimation.Description = args.Description
+		estimation, err := s.buildEstimation(args)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		if err := s.store.SaveEstimation(args.Path, estimation); err != nil {
 			return nil, nil, fmt.Errorf("failed to create estimation: %w", err)
 		}
 
+		result := fmt.Sprintf("Created estimation '%s' at %s with ID %s", args.Label, args.Path, estimation.ID)
+		if len(estimation.Ordering) > 0 {
+			result += fmt.Sprintf("\n\nTasks (%d):\n", len(estimation.Ordering))
+			for _, id := range estimation.Ordering {
+				result += fmt.Sprintf("- %s: %s\n", id, estimation.Tasks[id].Label)
+			}
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: fmt.Sprintf("Created estimation '%s' at %s with ID %s", args.Label, args.Path, estimation.ID)},
+				&mcp.TextContent{Text: result},
 			},
 		}, nil, nil
 	})
 }
 
+// buildEstimation builds the estimation described by the create_estimation arguments.
+// All categories and tasks are validated and every failure is reported in the returned error.
+func (s *Server) buildEstimation(args createEstimationArgs) (*model.Estimation, error) {
+	spec := &model.EstimationSpec{
+		Description: args.Description,
+		Tasks:       make([]model.TaskSpec, 0, len(args.Tasks)),
+	}
+
+	if len(args.Categories) > 0 {
+		spec.Categories = make(map[string]model.CategorySpec, len(args.Categories))
+		for i, cat := range args.Categories {
+			if cat.ID == "" {
+				return nil, fmt.Errorf("invalid spec: category #%d: id is required", i+1)
+			}
+			if _, exists := spec.Categories[cat.ID]; exists {
+				return nil, fmt.Errorf("invalid spec: category '%s': duplicate id", cat.ID)
+			}
+			spec.Categories[cat.ID] = model.CategorySpec{Label: cat.Label, CostPerTimeUnit: cat.CostPerTimeUnit}
+		}
+	}
+
+	for _, taskArgs := range args.Tasks {
+		spec.Tasks = append(spec.Tasks, model.TaskSpec{
+			Label:       taskArgs.Label,
+			Description: taskArgs.Description,
+			Category:    taskArgs.Category,
+			Assignee:    taskArgs.Assignee,
+			Optimistic:  taskArgs.Optimistic,
+			Likely:      taskArgs.Likely,
+			Pessimistic: taskArgs.Pessimistic,
+			Confidence:  taskArgs.Confidence,
+			FixedCost:   taskArgs.FixedCost,
+		})
+	}
+
+	return model.NewEstimationFromSpec(args.Label, spec, s.config)
+}
+
 // get_estimation tool
 type getEstimationArgs struct {
 	Path string `json:"path" jsonschema:"required,the file path to the estimation"`
@@ -173,6 +267,19 @@ func (s *Server) registerGetEstimationTool() {
 		if estimation.Description != "" {
 			result += fmt.Sprintf("Description: %s\n", estimation.Description)
 		}
+		if estimation.Owner != "" {
+			result += fmt.Sprintf("Owner: %s\n", estimation.Owner)
+		}
+		if estimation.TeamSize > 0 {
+			result += fmt.Sprintf("Team Size: %d\n", estimation.TeamSize)
+		}
+		if estimation.Locked {
+			result += "Locked: yes"
+			if estimation.ApprovedBy != "" {
+				result += fmt.Sprintf(" (approved by %s)", estimation.ApprovedBy)
+			}
+			result += "\n"
+		}
 		result += fmt.Sprintf("Tasks: %d\n", len(estimation.Tasks))
 		result += fmt.Sprintf("Created: %s\n", estimation.CreatedAt.Format("2006-01-02 15:04:05"))
 		result += fmt.Sprintf("Updated: %s\n", estimation.UpdatedAt.Format("2006-01-02 15:04:05"))
@@ -195,6 +302,12 @@ func (s *Server) registerDeleteEstimationTool() {
 		Name:        "delete_estimation",
 		Description: "Delete an estimation file",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteEstimationArgs) (*mcp.CallToolResult, any, error) {
+		if estimation, err := s.store.LoadEstimation(args.Path); err == nil {
+			if err := estimation.CheckUnlocked(); err != nil {
+				return nil, nil, err
+			}
+		}
+
 		if err := s.store.DeleteEstimation(args.Path); err != nil {
 			return nil, nil, fmt.Errorf("failed to delete estimation: %w", err)
 		}
@@ -209,44 +322,81 @@ func (s *Server) registerDeleteEstimationTool() {
 
 // get_estimation_summary tool
 type getEstimationSummaryArgs struct {
-	Path string `json:"path" jsonschema:"required,the file path to the estimation"`
+	Path       string `json:"path" jsonschema:"required,the file path to the estimation"`
+	TopTasks   int    `json:"topTasks,omitempty" jsonschema:"optional number of biggest tasks to include, defaults to 0 (none)"`
+	TopTasksBy string `json:"topTasksBy,omitempty" jsonschema:"optional ranking of the biggest tasks (cost or mean), defaults to cost"`
 }
 
+// Rankings of the biggest tasks of the estimation summary
+const (
+	topTasksByCost = "cost"
+	topTasksByMean = "mean"
+)
+
 func (s *Server) registerGetEstimationSummaryTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_estimation_summary",
-		Description: "Get a summary of the estimation with confidence intervals and cost estimates",
+		Description: "Get a summary of the estimation with confidence intervals and cost estimates. Set topTasks to also list its biggest tasks by cost or mean.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args getEstimationSummaryArgs) (*mcp.CallToolResult, any, error) {
+		if args.TopTasks < 0 {
+			return nil, nil, fmt.Errorf("invalid topTasks %d: must be >= 0", args.TopTasks)
+		}
+		if args.TopTasksBy == "" {
+			args.TopTasksBy = topTasksByCost
+		}
+		if args.TopTasksBy != topTasksByCost && args.TopTasksBy != topTasksByMean {
+			return nil, nil, fmt.Errorf("invalid topTasksBy '%s', expected %s or %s", args.TopTasksBy, topTasksByCost, topTasksByMean)
+		}
+
 		estimation, err := s.store.LoadEstimation(args.Path)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load estimation: %w", err)
 		}
 
-		projectEst := stats.CalculateProjectEstimation(estimation)
-		costs := stats.CalculateMinMaxCosts(estimation, s.config, stats.Confidence997)
-		distribution := stats.CalculateCategoryDistribution(estimation, s.config)
+		config := s.config.WithParams(estimation.Params)
+
+		// The numbers are the ones of the JSON output, rounded the same way
+		output := format.NewJSONFormatter(config).BuildOutput(estimation)
 
 		result := fmt.Sprintf("Project: %s\n", estimation.Label)
 		result += fmt.Sprintf("Tasks: %d\n\n", len(estimation.Tasks))
 
 		result += "Time Estimation:\n"
-		result += fmt.Sprintf("  99.7%% confidence: %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation*3, s.config.TimeUnit.Acronym)
-		result += fmt.Sprintf("  90%% confidence:   %.2f ± %.2f %s\n", projectEst.WeightedMean, projectEst.StandardDeviation*1.645, s.config.TimeUnit.Acronym)
-		result += fmt.Sprintf("  68%% confidence:   %.2f ± %.2f %s\n\n", projectEst.WeightedMean, projectEst.StandardDeviation, s.config.TimeUnit.Acronym)
+		for _, cl := range output.Statistics.ConfidenceLevels {
+			result += fmt.Sprintf("  %-17s %.2f ± %.2f %s\n", cl.Level+" confidence:", cl.Mean, cl.Deviation, config.TimeUnit.Acronym)
+		}
+		result += "\n"
 
-		if len(distribution) > 0 {
+		if len(output.CategoryDistribution) > 0 {
 			result += "Category Repartition:\n"
-			for _, dist := range distribution {
+			for _, dist := range output.CategoryDistribution {
 				if dist.Percentage > 0 {
-					result += fmt.Sprintf("  %s: %.1f%% (%.2f %s)\n", dist.CategoryLabel, dist.Percentage, dist.Time, s.config.TimeUnit.Acronym)
+					result += fmt.Sprintf("  %s: %.1f%% (%.2f %s)\n", dist.CategoryLabel, dist.Percentage, dist.Time, config.TimeUnit.Acronym)
 				}
 			}
 			result += "\n"
 		}
 
-		result += "Cost Estimation (99.7% confidence):\n"
-		result += fmt.Sprintf("  Maximum: %.2f %s (%.2f %s)\n", costs.Max.TotalCost, s.config.Currency, costs.Max.TotalTime, s.config.TimeUnit.Acronym)
-		result += fmt.Sprintf("  Minimum: %.2f %s (%.2f %s)\n", costs.Min.TotalCost, s.config.Currency, costs.Min.TotalTime, s.config.TimeUnit.Acronym)
+		if costs := output.Costs; costs != nil {
+			result += fmt.Sprintf("Cost Estimation (%s confidence):\n", costs.Confidence)
+			result += fmt.Sprintf("  Maximum: %.2f %s (%.2f %s)\n", costs.Max.Cost, config.Currency, costs.Max.Time, config.TimeUnit.Acronym)
+			result += fmt.Sprintf("  Minimum: %.2f %s (%.2f %s)\n", costs.Min.Cost, config.Currency, costs.Min.Time, config.TimeUnit.Acronym)
+			if costs.FixedCost > 0 {
+				result += fmt.Sprintf("  Including %.2f %s of fixed costs\n", costs.FixedCost, config.Currency)
+			}
+		}
+
+		if top := topTasks(estimation, config, args.TopTasks, args.TopTasksBy); len(top) > 0 {
+			outputs := taskOutputsByID(output)
+			result += fmt.Sprintf("\nTop %d tasks by %s:\n", len(top), args.TopTasksBy)
+			for _, task := range top {
+				result += fmt.Sprintf("  [%s] %s: %.2f %s", task.ID, task.Label, outputs[string(task.ID)].Calculated.WeightedMean, config.TimeUnit.Acronym)
+				if config.HasCosts() {
+					result += fmt.Sprintf(", %.2f %s", stats.CalculateTaskCost(task, config), config.Currency)
+				}
+				result += "\n"
+			}
+		}
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -256,15 +406,54 @@ func (s *Server) registerGetEstimationSummaryTool() {
 	})
 }
 
+// topTasks returns the n biggest tasks of the estimation by cost or mean, ties keeping
+// the task order
+func topTasks(estimation *model.Estimation, config *model.Config, n int, by string) []*model.Task {
+	if n <= 0 {
+		return nil
+	}
+
+	size := func(task *model.Task) float64 {
+		if by == topTasksByMean {
+			return task.WeightedMean()
+		}
+		return stats.CalculateTaskCost(task, config)
+	}
+
+	tasks := estimation.GetOrderedTasks()
+	sort.SliceStable(tasks, func(i, j int) bool { return size(tasks[i]) > size(tasks[j]) })
+
+	return tasks[:min(n, len(tasks))]
+}
+
+// taskOutputsByID indexes the tasks of a JSON output by ID
+func taskOutputsByID(output *format.Output) map[string]format.TaskOutput {
+	tasks := make(map[string]format.TaskOutput, len(output.Tasks))
+	for _, task := range output.Tasks {
+		tasks[task.ID] = task
+	}
+	return tasks
+}
+
+// formatTaskEstimates returns the estimates, mean and standard deviation line of a task,
+// rounded as in the JSON output
+func formatTaskEstimates(task format.TaskOutput) string {
+	return fmt.Sprintf("      O: %.2f, L: %.2f, P: %.2f => Mean: %.2f, SD: %.2f\n",
+		task.DisplayEstimations.Optimistic, task.DisplayEstimations.Likely, task.DisplayEstimations.Pessimistic,
+		task.Calculated.WeightedMean, task.Calculated.StandardDeviation)
+}
+
 // list_tasks tool
 type listTasksArgs struct {
-	Path string `json:"path" jsonschema:"required,the file path to the estimation"`
+	Path   string `json:"path" jsonschema:"required,the file path to the estimation"`
+	Offset int    `json:"offset,omitempty" jsonschema:"optional number of tasks to skip, defaults to 0"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"optional maximum number of tasks to return, defaults to all"`
 }
 
 func (s *Server) registerListTasksTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "list_tasks",
-		Description: "List all tasks in an estimation",
+		Description: "List all tasks in an estimation in their defined order, with their estimates and cost at the weighted mean. Use offset and limit to paginate.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args listTasksArgs) (*mcp.CallToolResult, any, error) {
 		estimation, err := s.store.LoadEstimation(args.Path)
 		if err != nil {
@@ -279,15 +468,102 @@ func (s *Server) registerListTasksTool() {
 			}, nil, nil
 		}
 
+		config := s.config.WithParams(estimation.Params)
+
+		tasks := estimation.GetOrderedTasks()
+		start, end, err := paginate(len(tasks), args.Offset, args.Limit)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		outputs := taskOutputsByID(format.NewJSONFormatter(config).BuildOutput(estimation))
+
 		result := "Tasks:\n"
+		for _, task := range tasks[start:end] {
+			cat := config.GetTaskCategory(task.Category)
+			result += fmt.Sprintf("  [%s] %s (%s)\n", task.ID, task.Label, cat.Label)
+			result += formatTaskEstimates(outputs[string(task.ID)])
+			if task.IsCapped() {
+				result += fmt.Sprintf("      Capped at %.2f %s\n", *task.MaxEstimate, config.TimeUnit.Acronym)
+			}
+			if task.Confidence != "" {
+				result += fmt.Sprintf("      Confidence: %s (SD x%g)\n", task.Confidence, config.GetTaskConfidenceFactor(task.Confidence))
+			}
+			if task.FixedCost > 0 {
+				result += fmt.Sprintf("      Fixed cost: %.2f %s\n", task.FixedCost, config.Currency)
+			}
+			if config.HasCosts() {
+				result += fmt.Sprintf("      Cost: %.2f %s (%.2f per %s)\n",
+					stats.CalculateTaskCost(task, config), config.Currency, cat.CostPerTimeUnit, config.TimeUnit.Acronym)
+			}
+		}
+		result += paginationNote(len(tasks), start, end)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: result},
+			},
+		}, nil, nil
+	})
+}
+
+// search_tasks tool
+type searchTasksArgs struct {
+	Path  string `json:"path" jsonschema:"required,the file path to the estimation"`
+	Query string `json:"query" jsonschema:"required,the text to search for in task labels and descriptions (case-insensitive)"`
+	Limit int    `json:"limit,omitempty" jsonschema:"optional maximum number of tasks to return, defaults to 20"`
+}
+
+// defaultSearchLimit is the default maximum number of tasks returned by search_tasks
+const defaultSearchLimit = 20
+
+func (s *Server) registerSearchTasksTool() {
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "search_tasks",
+		Description: "Search tasks of an estimation whose label or description contains the given text (case-insensitive). Returns task IDs usable with update_task and remove_task.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args searchTasksArgs) (*mcp.CallToolResult, any, error) {
+		query := strings.ToLower(strings.TrimSpace(args.Query))
+		if query == "" {
+			return nil, nil, fmt.Errorf("query must not be empty")
+		}
+
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultSearchLimit
+		}
+
+		estimation, err := s.store.LoadEstimation(args.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load estimation: %w", err)
+		}
+
+		config := s.config.WithParams(estimation.Params)
+
+		var matches []*model.Task
 		for _, task := range estimation.GetOrderedTasks() {
-			cat := s.config.GetTaskCategory(task.Category)
-			mean := task.WeightedMean()
-			sd := task.StandardDeviation()
+			if strings.Contains(strings.ToLower(task.Label), query) || strings.Contains(strings.ToLower(task.Description), query) {
+				matches = append(matches, task)
+			}
+		}
+
+		if len(matches) == 0 {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("No tasks matching '%s' found.", args.Query)},
+				},
+			}, nil, nil
+		}
+
+		outputs := taskOutputsByID(format.NewJSONFormatter(config).BuildOutput(estimation))
+
+		result := fmt.Sprintf("Tasks matching '%s':\n", args.Query)
+		for _, task := range matches[:min(limit, len(matches))] {
+			cat := config.GetTaskCategory(task.Category)
 			result += fmt.Sprintf("  [%s] %s (%s)\n", task.ID, task.Label, cat.Label)
-			result += fmt.Sprintf("      O: %.2f, L: %.2f, P: %.2f => Mean: %.2f, SD: %.2f\n",
-				task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic,
-				mean, sd)
+			result += formatTaskEstimates(outputs[string(task.ID)])
+		}
+		if len(matches) > limit {
+			result += fmt.Sprintf("\nResults truncated: showing %d of %d matching tasks, refine the query or raise the limit\n", limit, len(matches))
 		}
 
 		return &mcp.CallToolResult{
@@ -302,31 +578,74 @@ func (s *Server) registerListTasksTool() {
 type addTaskArgs struct {
 	Path        string  `json:"path" jsonschema:"required,the file path to the estimation"`
 	Label       string  `json:"label" jsonschema:"required,the task label"`
-	Category    string  `json:"category,omitempty" jsonschema:"optional task category, defaults to first category in config"`
+	Category    string  `json:"category,omitempty" jsonschema:"optional task category, defaults to the default category of the configuration merged with the estimation categories"`
+	Assignee    string  `json:"assignee,omitempty" jsonschema:"optional person assigned to the task"`
 	Optimistic  float64 `json:"optimistic,omitempty" jsonschema:"optional optimistic estimate, defaults to 0"`
 	Likely      float64 `json:"likely,omitempty" jsonschema:"optional likely estimate, defaults to 0"`
 	Pessimistic float64 `json:"pessimistic,omitempty" jsonschema:"optional pessimistic estimate, defaults to 0"`
+	Confidence  string  `json:"confidence,omitempty" jsonschema:"optional stability of the task requirements (high, medium or low), inflating its standard deviation"`
+	FixedCost   float64 `json:"fixedCost,omitempty" jsonschema:"optional certain cost unrelated to the effort (e.g. a license), defaults to 0"`
+	At          string  `json:"at,omitempty" jsonschema:"optional position of the task in the ordering: a number starting at 1, before:<taskId> or after:<taskId>, defaults to the end"`
+	Preview     bool    `json:"preview,omitempty" jsonschema:"optional, return the resulting estimations without saving the task"`
 }
 
 func (s *Server) registerAddTaskTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "add_task",
-		Description: "Add a new task to an estimation. If only some estimation values are provided, the missing ones will be auto-calculated using the configured multiplier (default 33%).",
+		Description: "Add a new task to an estimation. If only some estimation values are provided, the missing ones will be auto-calculated using the configured multiplier (default 33%). Set preview to check the auto-calculated values without saving.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args addTaskArgs) (*mcp.CallToolResult, any, error) {
-		estimation, _, err := s.store.LoadOrCreateEstimation(args.Path, args.Path)
+		var estimation *model.Estimation
+		var err error
+		if args.Preview {
+			// A preview doesn't create the estimation file
+			estimation, err = s.store.LoadEstimation(args.Path)
+			if os.IsNotExist(err) {
+				estimation, err = model.NewEstimation(args.Path), nil
+			}
+		} else {
+			estimation, _, err = s.store.LoadOrCreateEstimation(args.Path, args.Path)
+		}
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load estimation: %w", err)
 		}
 
+		// The estimation categories and settings apply to its tasks
+		config := s.config.WithParams(estimation.Params)
+
 		category := args.Category
 		if category == "" {
-			category = s.config.GetFirstCategoryID()
+			if category, err = config.GetDefaultCategoryID(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err := checkTaskConfidence(config, args.Confidence); err != nil {
+			return nil, nil, err
 		}
 
 		task := model.NewTask(args.Label, category)
-		task.SetEstimations(args.Optimistic, args.Likely, args.Pessimistic, s.config.GetAutoEstimationMultiplier())
+		task.Assignee = args.Assignee
+		task.Confidence = args.Confidence
+		task.FixedCost = args.FixedCost
+		task.SetEstimations(args.Optimistic, args.Likely, args.Pessimistic, config.GetAutoEstimationMultiplier())
+
+		if err := checkTask(config, task); err != nil {
+			return nil, nil, err
+		}
+
+		if args.Preview {
+			return previewTaskResult(task), nil, nil
+		}
+
+		if err := estimation.CheckUnlocked(); err != nil {
+			return nil, nil, err
+		}
 
-		estimation.AddTask(task)
+		index, err := estimation.ResolvePosition(args.At)
+		if err != nil {
+			return nil, nil, err
+		}
+		estimation.InsertTask(task, index)
 
 		if err := s.store.SaveEstimation(args.Path, estimation); err != nil {
 			return nil, nil, fmt.Errorf("failed to save estimation: %w", err)
@@ -350,33 +669,61 @@ type updateTaskArgs struct {
 	TaskID      string   `json:"taskId" jsonschema:"required,the task ID to update"`
 	Label       string   `json:"label,omitempty" jsonschema:"optional new task label"`
 	Category    string   `json:"category,omitempty" jsonschema:"optional new task category"`
+	Assignee    *string  `json:"assignee,omitempty" jsonschema:"optional new task assignee, empty to unassign"`
 	Optimistic  *float64 `json:"optimistic,omitempty" jsonschema:"optional new optimistic estimate"`
 	Likely      *float64 `json:"likely,omitempty" jsonschema:"optional new likely estimate"`
 	Pessimistic *float64 `json:"pessimistic,omitempty" jsonschema:"optional new pessimistic estimate"`
+	Confidence  *string  `json:"confidence,omitempty" jsonschema:"optional new stability of the task requirements (high, medium or low), empty to remove it"`
+	FixedCost   *float64 `json:"fixedCost,omitempty" jsonschema:"optional new certain cost unrelated to the effort, 0 to remove it"`
+	Preview     bool     `json:"preview,omitempty" jsonschema:"optional, return the resulting estimations without saving the task"`
 }
 
 func (s *Server) registerUpdateTaskTool() {
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "update_task",
-		Description: "Update an existing task in an estimation. If estimation values are updated, missing/invalid ones will be auto-calculated using the configured multiplier (default 33%).",
+		Description: "Update an existing task in an estimation. If estimation values are updated, missing/invalid ones will be auto-calculated using the configured multiplier (default 33%). Set preview to check the auto-calculated values without saving.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateTaskArgs) (*mcp.CallToolResult, any, error) {
 		estimation, err := s.store.LoadEstimation(args.Path)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to load estimation: %w", err)
 		}
 
+		if !args.Preview {
+			if err := estimation.CheckUnlocked(); err != nil {
+				return nil, nil, err
+			}
+		}
+
 		taskID := model.TaskID(args.TaskID)
 		task, ok := estimation.Tasks[taskID]
 		if !ok {
 			return nil, nil, fmt.Errorf("task with ID '%s' not found", args.TaskID)
 		}
 
+		// The estimation categories and settings apply to its tasks
+		config := s.config.WithParams(estimation.Params)
+
 		if args.Label != "" {
 			task.Label = args.Label
 		}
 		if args.Category != "" {
 			task.Category = args.Category
 		}
+		if args.Assignee != nil {
+			task.Assignee = *args.Assignee
+		}
+		if args.Confidence != nil {
+			if err := checkTaskConfidence(config, *args.Confidence); err != nil {
+				return nil, nil, err
+			}
+			task.Confidence = *args.Confidence
+		}
+		if args.FixedCost != nil {
+			if *args.FixedCost < 0 {
+				return nil, nil, fmt.Errorf("invalid fixed cost %g: must be >= 0", *args.FixedCost)
+			}
+			task.FixedCost = *args.FixedCost
+		}
 
 		// Check if any estimation values were provided
 		if args.Optimistic != nil || args.Likely != nil || args.Pessimistic != nil {
@@ -394,7 +741,15 @@ func (s *Server) registerUpdateTaskTool() {
 				p = *args.Pessimistic
 			}
 
-			task.SetEstimations(o, l, p, s.config.GetAutoEstimationMultiplier())
+			task.SetEstimations(o, l, p, config.GetAutoEstimationMultiplier())
+		}
+
+		if err := checkTask(config, task); err != nil {
+			return nil, nil, err
+		}
+
+		if args.Preview {
+			return previewTaskResult(task), nil, nil
 		}
 
 		estimation.UpdateTask(task)
@@ -415,6 +770,37 @@ func (s *Server) registerUpdateTaskTool() {
 	})
 }
 
+// previewTaskResult returns the estimations of a task that is not saved
+func previewTaskResult(task *model.Task) *mcp.CallToolResult {
+	result := fmt.Sprintf("Preview only, task not saved\nEstimations: O=%.2f, L=%.2f, P=%.2f",
+		task.Estimations.Optimistic, task.Estimations.Likely, task.Estimations.Pessimistic)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}
+}
+
+// checkTaskConfidence returns an error if the given task confidence level is not configured
+func checkTaskConfidence(config *model.Config, level string) error {
+	if level == "" || config.HasTaskConfidence(level) {
+		return nil
+	}
+	return fmt.Errorf("invalid confidence level '%s', expected one of: %s", level, strings.Join(config.GetTaskConfidenceLevels(), ", "))
+}
+
+// checkTask returns an error if the category of a task is unknown or the task is invalid
+func checkTask(config *model.Config, task *model.Task) error {
+	if !config.HasTaskCategory(task.Category) {
+		return fmt.Errorf("unknown category '%s'", task.Category)
+	}
+	if errors := task.Validate(); len(errors) > 0 {
+		return fmt.Errorf("invalid task: %s", strings.Join(errors, ", "))
+	}
+	return nil
+}
+
 // remove_task tool
 type removeTaskArgs struct {
 	Path   string `json:"path" jsonschema:"required,the file path to the estimation"`
@@ -431,6 +817,10 @@ func (s *Server) registerRemoveTaskTool() {
 			return nil, nil, fmt.Errorf("failed to load estimation: %w", err)
 		}
 
+		if err := estimation.CheckUnlocked(); err != nil {
+			return nil, nil, err
+		}
+
 		taskID := model.TaskID(args.TaskID)
 		if _, ok := estimation.Tasks[taskID]; !ok {
 			return nil, nil, fmt.Errorf("task with ID '%s' not found", args.TaskID)
@@ -461,12 +851,16 @@ func (s *Server) registerGetConfigTool() {
 		result := "Configuration:\n"
 		result += fmt.Sprintf("  Time Unit: %s (%s)\n", s.config.TimeUnit.Label, s.config.TimeUnit.Acronym)
 		result += fmt.Sprintf("  Currency: %s\n", s.config.Currency)
-		result += fmt.Sprintf("  Round Up Estimations: %v\n", s.config.RoundUpEstimations)
+		result += fmt.Sprintf("  Round Up Estimations: %v (%s, %s)\n", s.config.RoundUpEstimations, s.config.GetRoundingScope(), s.config.GetRoundingDirection())
 		result += fmt.Sprintf("  Auto Estimation Multiplier: %.0f%%\n\n", s.config.GetAutoEstimationMultiplier()*100)
 
 		result += "Task Categories:\n"
-		for id, cat := range s.config.TaskCategories {
-			result += fmt.Sprintf("  %s: %s (%.2f per %s)\n", id, cat.Label, cat.CostPerTimeUnit, s.config.TimeUnit.Acronym)
+		for _, id := range slices.Sorted(maps.Keys(s.config.TaskCategories)) {
+			cat := s.config.TaskCategories[id]
+			result += fmt.Sprintf("  %s: %s (%s per %s)\n", id, cat.Label, cat.FormatRate(), s.config.TimeUnit.Acronym)
+			if cat.Description != "" {
+				result += fmt.Sprintf("      %s\n", cat.Description)
+			}
 		}
 
 		return &mcp.CallToolResult{
@@ -476,3 +870,70 @@ func (s *Server) registerGetConfigTool() {
 		}, nil, nil
 	})
 }
+
+func (s *Server) registerPrompts() {
+	s.registerEstimateFeaturePrompt()
+}
+
+// estimate_feature prompt
+func (s *Server) registerEstimateFeaturePrompt() {
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "estimate_feature",
+		Description: "Break a feature down into tasks with three-point estimates using the configured categories",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "feature", Description: "the description of the feature to estimate", Required: true},
+			{Name: "path", Description: "optional path of the estimation file the tasks should be added to"},
+		},
+	}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		feature := strings.TrimSpace(req.Params.Arguments["feature"])
+		if feature == "" {
+			return nil, fmt.Errorf("feature is required")
+		}
+
+		path := req.Params.Arguments["path"]
+		if path == "" {
+			path = "<path of the estimation file>"
+		}
+
+		// Sort categories for a stable prompt
+		categoryIDs := make([]string, 0, len(s.config.TaskCategories))
+		for id := range s.config.TaskCategories {
+			categoryIDs = append(categoryIDs, id)
+		}
+		sort.Strings(categoryIDs)
+
+		var sb strings.Builder
+		sb.WriteString("Break the following feature down into tasks and estimate each of them.\n\n")
+		sb.WriteString(fmt.Sprintf("Feature:\n%s\n\n", feature))
+
+		sb.WriteString("Each task needs a three-point estimate expressed in ")
+		sb.WriteString(fmt.Sprintf("%s (%s):\n", s.config.TimeUnit.Label, s.config.TimeUnit.Acronym))
+		sb.WriteString("  - optimistic: the duration if everything goes well\n")
+		sb.WriteString("  - likely: the most probable duration\n")
+		sb.WriteString("  - pessimistic: the duration if things go wrong\n")
+		sb.WriteString("with optimistic <= likely <= pessimistic.\n\n")
+
+		sb.WriteString("Assign each task to one of the following category ids:\n")
+		for _, id := range categoryIDs {
+			cat := s.config.TaskCategories[id]
+			sb.WriteString(fmt.Sprintf("  - %s: %s", id, cat.Label))
+			if cat.Description != "" {
+				sb.WriteString(fmt.Sprintf(" (%s)", cat.Description))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+
+		sb.WriteString(fmt.Sprintf("Then add every task to %s by calling the add_task tool with the arguments ", path))
+		sb.WriteString("path, label, category, optimistic, likely and pessimistic, for example:\n")
+		sb.WriteString(fmt.Sprintf(`  {"path": %q, "label": "Implement the API endpoint", "category": %q, "optimistic": 1, "likely": 2, "pessimistic": 4}`, path, s.config.GetFirstCategoryID()))
+		sb.WriteString("\n\nFinally, call get_estimation_summary to report the resulting estimation.\n")
+
+		return &mcp.GetPromptResult{
+			Description: "Estimate a feature as a list of tasks",
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: sb.String()}},
+			},
+		}, nil
+	})
+}
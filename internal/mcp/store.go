@@ -7,12 +7,14 @@ import (
 	"path/filepath"
 
 	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/store"
 	"gopkg.in/yaml.v3"
 )
 
 // ChrootedStore is a store that is restricted to a specific directory
 type ChrootedStore struct {
-	root *os.Root
+	root        *os.Root
+	maxFileSize int64
 }
 
 // NewChrootedStore creates a new store restricted to the given directory
@@ -23,10 +25,18 @@ func NewChrootedStore(dir string) (*ChrootedStore, error) {
 	}
 
 	return &ChrootedStore{
-		root: root,
+		root:        root,
+		maxFileSize: store.DefaultMaxFileSize,
 	}, nil
 }
 
+// WithMaxFileSize sets the maximum size of the estimation files read by the store, in bytes.
+// A size <= 0 disables the limit.
+func (s *ChrootedStore) WithMaxFileSize(size int64) *ChrootedStore {
+	s.maxFileSize = size
+	return s
+}
+
 // Close closes the root directory
 func (s *ChrootedStore) Close() error {
 	return s.root.Close()
@@ -46,32 +56,22 @@ func (s *ChrootedStore) writeFile(path string, data []byte) error {
 
 // LoadEstimation loads an estimation from a file
 func (s *ChrootedStore) LoadEstimation(path string) (*model.Estimation, error) {
-	data, err := fs.ReadFile(s.root.FS(), path)
+	data, err := store.ReadFileLimited(s.root.FS(), path, s.maxFileSize)
 	if err != nil {
 		return nil, err
 	}
 
-	estimation := &model.Estimation{}
-	if err := yaml.Unmarshal(data, estimation); err != nil {
+	estimation, err := store.DecodeEstimation(data)
+	if err != nil {
 		return nil, err
 	}
 
-	// Ensure tasks map is initialized
-	if estimation.Tasks == nil {
-		estimation.Tasks = make(map[model.TaskID]*model.Task)
-	}
-
-	// Ensure ordering is initialized
-	if estimation.Ordering == nil {
-		estimation.Ordering = []model.TaskID{}
-	}
-
 	return estimation, nil
 }
 
 // LoadOrCreateEstimation loads an estimation from a file, or creates a new one if it doesn't exist
 func (s *ChrootedStore) LoadOrCreateEstimation(path string, label string) (*model.Estimation, bool, error) {
-	data, err := fs.ReadFile(s.root.FS(), path)
+	data, err := store.ReadFileLimited(s.root.FS(), path, s.maxFileSize)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Create new estimation
@@ -84,21 +84,11 @@ func (s *ChrootedStore) LoadOrCreateEstimation(path string, label string) (*mode
 		return nil, false, err
 	}
 
-	estimation := &model.Estimation{}
-	if err := yaml.Unmarshal(data, estimation); err != nil {
+	estimation, err := store.DecodeEstimation(data)
+	if err != nil {
 		return nil, false, err
 	}
 
-	// Ensure tasks map is initialized
-	if estimation.Tasks == nil {
-		estimation.Tasks = make(map[model.TaskID]*model.Task)
-	}
-
-	// Ensure ordering is initialized
-	if estimation.Ordering == nil {
-		estimation.Ordering = []model.TaskID{}
-	}
-
 	return estimation, false, nil
 }
 
@@ -143,11 +133,8 @@ func (s *ChrootedStore) ListEstimations(dir string) ([]string, error) {
 
 	var files []string
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".yml" {
-			// Check if it's an estimation file (ends with .estimation.yml)
-			if filepath.Ext(filepath.Base(entry.Name()[:len(entry.Name())-4])) == ".estimation" {
-				files = append(files, entry.Name())
-			}
+		if !entry.IsDir() && store.IsEstimationFile(entry.Name()) {
+			files = append(files, entry.Name())
 		}
 	}
 
@@ -1,8 +1,111 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoCategories is returned when adding a task without any task category configured
+var ErrNoCategories = errors.New("no task category configured, add one with 'config category add'")
+
 // DefaultAutoEstimationMultiplier is the default multiplier for auto-estimation (33%)
 const DefaultAutoEstimationMultiplier = 0.33
 
+// DefaultWideRangeRatio is the default pessimistic/optimistic ratio above which a range is reported as suspiciously wide
+const DefaultWideRangeRatio = 10
+
+// Estimation models, defining how the three points relate to the task distribution
+const (
+	// EstimationModelPERT6Sigma treats optimistic and pessimistic as the absolute best and
+	// worst cases, 6 standard deviations apart: SD = (P - O) / 6
+	EstimationModelPERT6Sigma = "pert-6sigma"
+	// EstimationModelPERTP10P90 treats optimistic and pessimistic as the 10th and 90th
+	// percentiles of a normal distribution, 2 × 1.2816 standard deviations apart: SD = (P - O) / 2.563
+	EstimationModelPERTP10P90 = "pert-p10p90"
+)
+
+// Cost models, defining how the min and max costs are derived from the estimation
+const (
+	// CostModelProportional spreads the project min and max times over the categories
+	// proportionally to their share of the weighted mean
+	CostModelProportional = "proportional"
+	// CostModelIndependent derives the min and max times of each category from its own
+	// weighted mean and standard deviation, then sums them
+	CostModelIndependent = "independent"
+)
+
+// Rounding scopes, telling which values are rounded up when RoundUpEstimations is set
+const (
+	// RoundingScopePerTask rounds up every task and category value as well as the totals,
+	// so that the displayed totals may exceed the sum of the displayed parts
+	RoundingScopePerTask = "per-task"
+	// RoundingScopeProjectTotal only rounds up the project totals, the task and category
+	// values being shown unrounded
+	RoundingScopeProjectTotal = "project-total"
+)
+
+// Rounding directions, telling how the displayed values are rounded when the task
+// values are rounded up (see RoundUpParts)
+const (
+	// RoundingDirectionUp rounds up the calculated values, the optimistic, likely and
+	// pessimistic estimates being displayed as entered
+	RoundingDirectionUp = "up"
+	// RoundingDirectionBounds also displays the estimates rounded toward their bound:
+	// optimistic floored, likely rounded to the nearest and pessimistic ceiled
+	RoundingDirectionBounds = "bounds"
+)
+
+// p10p90Divisor is the number of standard deviations between the 10th and 90th percentiles
+const p10p90Divisor = 2 * 1.2816
+
+// StandardDeviationDivisor returns the number of standard deviations between the optimistic
+// and pessimistic estimates of the given estimation model, 6 for unknown models
+func StandardDeviationDivisor(estimationModel string) float64 {
+	if estimationModel == EstimationModelPERTP10P90 {
+		return p10p90Divisor
+	}
+	return 6
+}
+
+// DefaultCategoryCost is the default cost per time unit of new and unknown task categories
+const DefaultCategoryCost = 500
+
+// DefaultBaselineThreshold is the default growth over a baseline, in percent, above
+// which summary --baseline reports a scope creep
+const DefaultBaselineThreshold = 10
+
+// DefaultPreviewDebounce is the default delay after the last edit before the TUI preview is recomputed
+const DefaultPreviewDebounce = 100 * time.Millisecond
+
+// Task confidence levels, from the most to the least stable requirements
+const (
+	TaskConfidenceHigh   = "high"
+	TaskConfidenceMedium = "medium"
+	TaskConfidenceLow    = "low"
+)
+
+// defaultTaskConfidenceFactors are the default standard deviation inflation factors of the task confidence levels
+var defaultTaskConfidenceFactors = map[string]float64{
+	TaskConfidenceHigh:   1,
+	TaskConfidenceMedium: 1.2,
+	TaskConfidenceLow:    1.5,
+}
+
+// DefaultWorkingDaysPerWeek is the default number of working days in a calendar week
+const DefaultWorkingDaysPerWeek = 5
+
+// DefaultHoursPerDay is the default number of working hours in a day
+const DefaultHoursPerDay = 8
+
+// DefaultNarrowRangeThreshold is the default estimate above which a range without uncertainty is reported
+const DefaultNarrowRangeThreshold = 1
+
 // Config represents the application configuration stored in .guesstimate/config.yml
 type Config struct {
 	TaskCategories           map[string]TaskCategory `yaml:"taskCategories"`
@@ -10,6 +113,29 @@ type Config struct {
 	Currency                 string                  `yaml:"currency"`
 	RoundUpEstimations       bool                    `yaml:"roundUpEstimations"`
 	AutoEstimationMultiplier float64                 `yaml:"autoEstimationMultiplier,omitempty"`
+	ConfidenceLevels         map[string]float64      `yaml:"confidenceLevels,omitempty"`
+	WideRangeRatio           float64                 `yaml:"wideRangeRatio,omitempty"`
+	NarrowRangeThreshold     float64                 `yaml:"narrowRangeThreshold,omitempty"`
+	CorrelationCoefficient   float64                 `yaml:"correlationCoefficient,omitempty"`
+	CostConfidence           string                  `yaml:"costConfidence,omitempty"`
+	PreviewDebounce          time.Duration           `yaml:"previewDebounce,omitempty"`
+	EstimationModel          string                  `yaml:"estimationModel,omitempty"`
+	CostModel                string                  `yaml:"costModel,omitempty"`
+	TaskConfidenceFactors    map[string]float64      `yaml:"taskConfidenceFactors,omitempty"`
+	Language                 string                  `yaml:"language,omitempty"`
+	WorkingDaysPerWeek       float64                 `yaml:"workingDaysPerWeek,omitempty"`
+	HoursPerDay              float64                 `yaml:"hoursPerDay,omitempty"`
+	Velocity                 float64                 `yaml:"velocity,omitempty"`
+	DefaultCostPerTimeUnit   *float64                `yaml:"defaultCostPerTimeUnit,omitempty"`
+	TrapCtrlC                *bool                   `yaml:"trapCtrlC,omitempty"`
+	ShowCost                 *bool                   `yaml:"showCost,omitempty"`
+	TaskTableColumns         []string                `yaml:"taskTableColumns,omitempty"`
+	DefaultEstimation        string                  `yaml:"defaultEstimation,omitempty"`
+	RoundingScope            string                  `yaml:"roundingScope,omitempty"`
+	RoundingDirection        string                  `yaml:"roundingDirection,omitempty"`
+	BaselineThreshold        float64                 `yaml:"baselineThreshold,omitempty"`
+	Markup                   float64                 `yaml:"markup,omitempty"`
+	Discount                 float64                 `yaml:"discount,omitempty"`
 }
 
 // TaskCategory represents a category of tasks with associated cost
@@ -17,6 +143,17 @@ type TaskCategory struct {
 	ID              string  `yaml:"-"`
 	Label           string  `yaml:"label"`
 	CostPerTimeUnit float64 `yaml:"costPerTimeUnit"`
+	Description     string  `yaml:"description,omitempty"`
+	Currency        string  `yaml:"currency,omitempty"`
+}
+
+// FormatRate returns the cost per time unit of the category, followed by
+// its own currency when it has one
+func (c TaskCategory) FormatRate() string {
+	if c.Currency == "" {
+		return fmt.Sprintf("%.2f", c.CostPerTimeUnit)
+	}
+	return fmt.Sprintf("%.2f %s", c.CostPerTimeUnit, c.Currency)
 }
 
 // TimeUnit represents the time unit configuration
@@ -25,6 +162,9 @@ type TimeUnit struct {
 	Acronym string `yaml:"acronym"`
 }
 
+// ManDay is the default time unit, in which story points are converted
+var ManDay = TimeUnit{Label: "man-day", Acronym: "md"}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -45,14 +185,174 @@ func DefaultConfig() *Config {
 				CostPerTimeUnit: 500,
 			},
 		},
-		TimeUnit: TimeUnit{
-			Label:   "man-day",
-			Acronym: "md",
-		},
+		TimeUnit:                 ManDay,
 		Currency:                 "€ H.T.",
 		RoundUpEstimations:       true,
 		AutoEstimationMultiplier: DefaultAutoEstimationMultiplier,
+		ConfidenceLevels: map[string]float64{
+			"68%":   1,
+			"90%":   1.645,
+			"99.7%": 3,
+		},
+		WideRangeRatio:        DefaultWideRangeRatio,
+		NarrowRangeThreshold:  DefaultNarrowRangeThreshold,
+		TaskConfidenceFactors: maps.Clone(defaultTaskConfidenceFactors),
+	}
+}
+
+// ConfigOption customizes a configuration built by NewConfig
+type ConfigOption func(c *Config) error
+
+// NewConfig returns the default configuration customized by the given options, applied
+// in order, or the error of the first failing option
+func NewConfig(opts ...ConfigOption) (*Config, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			return nil, err
+		}
 	}
+	return config, nil
+}
+
+// WithoutCategories removes the task categories, e.g. before adding others with WithCategory
+func WithoutCategories() ConfigOption {
+	return func(c *Config) error {
+		c.TaskCategories = make(map[string]TaskCategory)
+		return nil
+	}
+}
+
+// WithCategory adds a task category, see AddCategory
+func WithCategory(id string, label string, costPerTimeUnit float64) ConfigOption {
+	return func(c *Config) error {
+		if err := c.AddCategory(id, label, costPerTimeUnit); err != nil {
+			return fmt.Errorf("category '%s': %w", id, err)
+		}
+		return nil
+	}
+}
+
+// WithTimeUnit sets the time unit
+func WithTimeUnit(timeUnit TimeUnit) ConfigOption {
+	return func(c *Config) error {
+		c.TimeUnit = timeUnit
+		return nil
+	}
+}
+
+// WithCurrency sets the currency
+func WithCurrency(currency string) ConfigOption {
+	return func(c *Config) error {
+		c.Currency = currency
+		return nil
+	}
+}
+
+// WithRoundUpEstimations sets whether the estimations are rounded up
+func WithRoundUpEstimations(roundUp bool) ConfigOption {
+	return func(c *Config) error {
+		c.RoundUpEstimations = roundUp
+		return nil
+	}
+}
+
+// AddCategory adds a task category, keeping its ID in sync with its key. It fails if the
+// ID is empty or already used, or if the cost is negative.
+func (c *Config) AddCategory(id string, label string, costPerTimeUnit float64) error {
+	if c.TaskCategories == nil {
+		c.TaskCategories = make(map[string]TaskCategory)
+	}
+	return addCategory(c.TaskCategories, id, label, costPerTimeUnit)
+}
+
+// addCategory adds a task category to the given categories, see AddCategory
+func addCategory(categories map[string]TaskCategory, id string, label string, costPerTimeUnit float64) error {
+	if id == "" {
+		return errors.New("id is required")
+	}
+	if _, exists := categories[id]; exists {
+		return errors.New("duplicate id")
+	}
+	if costPerTimeUnit < 0 {
+		return errors.New("cost per time unit must be >= 0")
+	}
+
+	categories[id] = TaskCategory{
+		ID:              id,
+		Label:           label,
+		CostPerTimeUnit: costPerTimeUnit,
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of the configuration
+func (c *Config) Clone() *Config {
+	clone := *c
+
+	if c.TaskCategories != nil {
+		clone.TaskCategories = make(map[string]TaskCategory, len(c.TaskCategories))
+		for id, cat := range c.TaskCategories {
+			clone.TaskCategories[id] = cat
+		}
+	}
+	if c.ConfidenceLevels != nil {
+		clone.ConfidenceLevels = make(map[string]float64, len(c.ConfidenceLevels))
+		for name, multiplier := range c.ConfidenceLevels {
+			clone.ConfidenceLevels[name] = multiplier
+		}
+	}
+	if c.TaskConfidenceFactors != nil {
+		clone.TaskConfidenceFactors = maps.Clone(c.TaskConfidenceFactors)
+	}
+	clone.TaskTableColumns = slices.Clone(c.TaskTableColumns)
+	clone.DefaultCostPerTimeUnit = clonePointer(c.DefaultCostPerTimeUnit)
+	clone.TrapCtrlC = clonePointer(c.TrapCtrlC)
+	clone.ShowCost = clonePointer(c.ShowCost)
+
+	return &clone
+}
+
+// WithParams returns the configuration with the given estimation-specific parameters applied.
+// Every command loading an estimation applies its parameters, so that the categories an
+// estimation defines for itself (e.g. from a spec) are known. The parameter categories are
+// added to the configured ones, replacing those with the same ID. The receiver is left
+// untouched.
+func (c *Config) WithParams(params *EstimationParams) *Config {
+	if params == nil {
+		return c
+	}
+
+	merged := *c
+
+	if len(params.TaskCategories) > 0 {
+		merged.TaskCategories = make(map[string]TaskCategory, len(c.TaskCategories)+len(params.TaskCategories))
+		for id, cat := range c.TaskCategories {
+			merged.TaskCategories[id] = cat
+		}
+		for id, cat := range params.TaskCategories {
+			cat.ID = id
+			merged.TaskCategories[id] = cat
+		}
+	}
+	if params.TimeUnit != nil {
+		merged.TimeUnit = *params.TimeUnit
+	}
+	if params.Currency != "" {
+		merged.Currency = params.Currency
+	}
+	if params.RoundUpEstimations != nil {
+		merged.RoundUpEstimations = *params.RoundUpEstimations
+	}
+	if params.Markup != nil {
+		merged.Markup = *params.Markup
+	}
+	if params.Discount != nil {
+		merged.Discount = *params.Discount
+	}
+
+	return &merged
 }
 
 // GetAutoEstimationMultiplier returns the configured multiplier or the default
@@ -63,6 +363,146 @@ func (c *Config) GetAutoEstimationMultiplier() float64 {
 	return c.AutoEstimationMultiplier
 }
 
+// GetWideRangeRatio returns the configured wide range ratio or the default.
+// A negative value disables the warning.
+func (c *Config) GetWideRangeRatio() float64 {
+	if c.WideRangeRatio == 0 {
+		return DefaultWideRangeRatio
+	}
+	return c.WideRangeRatio
+}
+
+// GetBaselineThreshold returns the configured baseline growth threshold, in percent,
+// or the default. A negative value disables the check.
+func (c *Config) GetBaselineThreshold() float64 {
+	if c.BaselineThreshold == 0 {
+		return DefaultBaselineThreshold
+	}
+	return c.BaselineThreshold
+}
+
+// GetNarrowRangeThreshold returns the configured narrow range threshold or the default.
+// A negative value disables the warning.
+func (c *Config) GetNarrowRangeThreshold() float64 {
+	if c.NarrowRangeThreshold == 0 {
+		return DefaultNarrowRangeThreshold
+	}
+	return c.NarrowRangeThreshold
+}
+
+// GetCorrelationCoefficient returns the configured correlation coefficient between
+// tasks, clamped to [0, 1] (0 meaning independent tasks)
+func (c *Config) GetCorrelationCoefficient() float64 {
+	return math.Max(0, math.Min(1, c.CorrelationCoefficient))
+}
+
+// GetEstimationModel returns the configured estimation model, defaulting to
+// EstimationModelPERT6Sigma when unset (unknown models are rejected by Validate)
+func (c *Config) GetEstimationModel() string {
+	if c.EstimationModel == EstimationModelPERTP10P90 {
+		return EstimationModelPERTP10P90
+	}
+	return EstimationModelPERT6Sigma
+}
+
+// GetCostModel returns the configured cost model, defaulting to CostModelProportional
+// when unset or unknown
+func (c *Config) GetCostModel() string {
+	if c.CostModel == CostModelIndependent {
+		return CostModelIndependent
+	}
+	return CostModelProportional
+}
+
+// taskConfidenceFactors returns the configured task confidence factors, or the default ones
+func (c *Config) taskConfidenceFactors() map[string]float64 {
+	if len(c.TaskConfidenceFactors) > 0 {
+		return c.TaskConfidenceFactors
+	}
+	return defaultTaskConfidenceFactors
+}
+
+// GetTaskConfidenceFactor returns the standard deviation inflation factor of the given
+// task confidence level, 1 for tasks without a level or with an unknown one
+func (c *Config) GetTaskConfidenceFactor(level string) float64 {
+	if factor, ok := c.taskConfidenceFactors()[level]; ok && factor > 0 {
+		return factor
+	}
+	return 1
+}
+
+// HasTaskConfidence returns true if the given task confidence level is known
+func (c *Config) HasTaskConfidence(level string) bool {
+	_, ok := c.taskConfidenceFactors()[level]
+	return ok
+}
+
+// GetTaskConfidenceLevels returns the known task confidence levels, from the smallest to the largest factor
+func (c *Config) GetTaskConfidenceLevels() []string {
+	factors := c.taskConfidenceFactors()
+	levels := slices.Collect(maps.Keys(factors))
+	sort.Slice(levels, func(i, j int) bool {
+		if factors[levels[i]] == factors[levels[j]] {
+			return levels[i] < levels[j]
+		}
+		return factors[levels[i]] < factors[levels[j]]
+	})
+	return levels
+}
+
+// GetWorkingDaysPerWeek returns the configured number of working days per week or the default
+func (c *Config) GetWorkingDaysPerWeek() float64 {
+	if c.WorkingDaysPerWeek <= 0 {
+		return DefaultWorkingDaysPerWeek
+	}
+	return c.WorkingDaysPerWeek
+}
+
+// GetHoursPerDay returns the configured number of working hours per day or the default
+func (c *Config) GetHoursPerDay() float64 {
+	if c.HoursPerDay <= 0 {
+		return DefaultHoursPerDay
+	}
+	return c.HoursPerDay
+}
+
+// IsHourTimeUnit returns true if the time unit counts hours (e.g. "man-hour" or "h")
+// rather than days
+func (c *Config) IsHourTimeUnit() bool {
+	switch strings.ToLower(c.TimeUnit.Acronym) {
+	case "h", "mh", "hr", "hrs":
+		return true
+	}
+	return strings.Contains(strings.ToLower(c.TimeUnit.Label), "hour")
+}
+
+// IsStoryPointTimeUnit returns true if the time unit counts story points (e.g. "story point" or "sp")
+// rather than time
+func (c *Config) IsStoryPointTimeUnit() bool {
+	switch strings.ToLower(c.TimeUnit.Acronym) {
+	case "sp", "pt", "pts":
+		return true
+	}
+	return strings.Contains(strings.ToLower(c.TimeUnit.Label), "point")
+}
+
+// ToWorkingDays converts a value expressed in the time unit into working days
+func (c *Config) ToWorkingDays(value float64) float64 {
+	if c.IsHourTimeUnit() {
+		return value / c.GetHoursPerDay()
+	}
+	return value
+}
+
+// GetPreviewDebounce returns the configured TUI preview debounce interval or the default.
+// A negative value disables the debounce.
+func (c *Config) GetPreviewDebounce() time.Duration {
+	if c.PreviewDebounce == 0 {
+		return DefaultPreviewDebounce
+	}
+	return c.PreviewDebounce
+}
+
 // GetTaskCategory returns a task category by ID, or a default one if not found
 func (c *Config) GetTaskCategory(id string) TaskCategory {
 	if cat, ok := c.TaskCategories[id]; ok {
@@ -71,8 +511,111 @@ func (c *Config) GetTaskCategory(id string) TaskCategory {
 	return TaskCategory{
 		ID:              id,
 		Label:           id,
-		CostPerTimeUnit: 500,
+		CostPerTimeUnit: c.GetDefaultCostPerTimeUnit(),
+	}
+}
+
+// HasTaskCategory returns true if the given task category is configured
+func (c *Config) HasTaskCategory(id string) bool {
+	_, ok := c.TaskCategories[id]
+	return ok
+}
+
+// GetDefaultCostPerTimeUnit returns the cost per time unit of new and unknown task
+// categories, defaulting to DefaultCategoryCost when unset
+func (c *Config) GetDefaultCostPerTimeUnit() float64 {
+	if c.DefaultCostPerTimeUnit != nil && *c.DefaultCostPerTimeUnit >= 0 {
+		return *c.DefaultCostPerTimeUnit
+	}
+	return DefaultCategoryCost
+}
+
+// GetRoundingScope returns the configured rounding scope, defaulting to
+// RoundingScopePerTask when unset (unknown scopes are rejected by Validate)
+func (c *Config) GetRoundingScope() string {
+	if c.RoundingScope == RoundingScopeProjectTotal {
+		return RoundingScopeProjectTotal
 	}
+	return RoundingScopePerTask
+}
+
+// RoundUpParts returns true if the task and category values are rounded up, and not only
+// the project totals
+func (c *Config) RoundUpParts() bool {
+	return c.RoundUpEstimations && c.GetRoundingScope() == RoundingScopePerTask
+}
+
+// GetRoundingDirection returns the configured rounding direction, defaulting to
+// RoundingDirectionUp when unset (unknown directions are rejected by Validate)
+func (c *Config) GetRoundingDirection() string {
+	if c.RoundingDirection == RoundingDirectionBounds {
+		return RoundingDirectionBounds
+	}
+	return RoundingDirectionUp
+}
+
+// DisplayEstimations returns the estimates as displayed, rounded toward their bound
+// with the RoundingDirectionBounds direction. The stored values are left untouched.
+func (c *Config) DisplayEstimations(estimations Estimations) Estimations {
+	if !c.RoundUpParts() || c.GetRoundingDirection() != RoundingDirectionBounds {
+		return estimations
+	}
+	return Estimations{
+		Optimistic:  math.Floor(estimations.Optimistic),
+		Likely:      math.Round(estimations.Likely),
+		Pessimistic: math.Ceil(estimations.Pessimistic),
+	}
+}
+
+// Validate returns the invalid settings of the configuration
+func (c *Config) Validate() []string {
+	var errors []string
+
+	if c.Markup < 0 {
+		errors = append(errors, "markup must be >= 0")
+	}
+	if c.Discount < 0 || c.Discount > 100 {
+		errors = append(errors, "discount must be between 0 and 100")
+	}
+	switch c.EstimationModel {
+	case "", EstimationModelPERT6Sigma, EstimationModelPERTP10P90:
+	default:
+		errors = append(errors, fmt.Sprintf("unknown estimationModel '%s', expected %s or %s", c.EstimationModel, EstimationModelPERT6Sigma, EstimationModelPERTP10P90))
+	}
+	switch c.RoundingScope {
+	case "", RoundingScopePerTask, RoundingScopeProjectTotal:
+	default:
+		errors = append(errors, fmt.Sprintf("unknown roundingScope '%s', expected %s or %s", c.RoundingScope, RoundingScopePerTask, RoundingScopeProjectTotal))
+	}
+	switch c.RoundingDirection {
+	case "", RoundingDirectionUp, RoundingDirectionBounds:
+	default:
+		errors = append(errors, fmt.Sprintf("unknown roundingDirection '%s', expected %s or %s", c.RoundingDirection, RoundingDirectionUp, RoundingDirectionBounds))
+	}
+
+	return errors
+}
+
+// HasCostAdjustments returns true if a markup or a discount applies to the costs
+func (c *Config) HasCostAdjustments() bool {
+	return c.Markup != 0 || c.Discount != 0
+}
+
+// GetTrapCtrlC returns true if Ctrl+C is ignored by the TUI, which is the default
+func (c *Config) GetTrapCtrlC() bool {
+	return c.TrapCtrlC == nil || *c.TrapCtrlC
+}
+
+// GetShowCost returns true if the costs are computed and reported, which is the default.
+// When false, only the time estimations and their distribution are reported.
+func (c *Config) GetShowCost() bool {
+	return c.ShowCost == nil || *c.ShowCost
+}
+
+// HasCosts returns true if the costs are reported: they are shown and the time unit isn't
+// story points, which have no cost until converted into time (see Velocity)
+func (c *Config) HasCosts() bool {
+	return c.GetShowCost() && !c.IsStoryPointTimeUnit()
 }
 
 // GetFirstCategoryID returns the ID of the first task category
@@ -82,3 +625,12 @@ func (c *Config) GetFirstCategoryID() string {
 	}
 	return ""
 }
+
+// GetDefaultCategoryID returns the category of the tasks added without one, which is the
+// first task category, or ErrNoCategories if none is configured
+func (c *Config) GetDefaultCategoryID() (string, error) {
+	if len(c.TaskCategories) == 0 {
+		return "", ErrNoCategories
+	}
+	return c.GetFirstCategoryID(), nil
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(c *Config)
+		wantErr string
+	}{
+		{name: "default", edit: func(c *Config) {}},
+		{name: "cost adjustments", edit: func(c *Config) { c.Markup, c.Discount = 15, 100 }},
+		{name: "negative markup", edit: func(c *Config) { c.Markup = -1 }, wantErr: "markup must be >= 0"},
+		{name: "negative discount", edit: func(c *Config) { c.Discount = -5 }, wantErr: "discount must be between 0 and 100"},
+		{name: "discount over 100", edit: func(c *Config) { c.Discount = 150 }, wantErr: "discount must be between 0 and 100"},
+		{name: "estimation model", edit: func(c *Config) { c.EstimationModel = EstimationModelPERTP10P90 }},
+		{name: "unknown estimation model", edit: func(c *Config) { c.EstimationModel = "pert-p5p95" }, wantErr: "unknown estimationModel 'pert-p5p95'"},
+		{name: "rounding scope", edit: func(c *Config) { c.RoundingScope = RoundingScopeProjectTotal }},
+		{name: "unknown rounding scope", edit: func(c *Config) { c.RoundingScope = "total" }, wantErr: "unknown roundingScope 'total'"},
+		{name: "rounding direction", edit: func(c *Config) { c.RoundingDirection = RoundingDirectionBounds }},
+		{name: "unknown rounding direction", edit: func(c *Config) { c.RoundingDirection = "down" }, wantErr: "unknown roundingDirection 'down'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.edit(config)
+
+			errors := strings.Join(config.Validate(), ", ")
+			if tt.wantErr == "" && errors != "" {
+				t.Errorf("Validate() = %q, want no error", errors)
+			}
+			if tt.wantErr != "" && !strings.Contains(errors, tt.wantErr) {
+				t.Errorf("Validate() = %q, want %q", errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithParamsCostAdjustments(t *testing.T) {
+	zero, ten := 0.0, 10.0
+
+	tests := []struct {
+		name         string
+		params       *EstimationParams
+		wantMarkup   float64
+		wantDiscount float64
+	}{
+		{name: "no params", params: nil, wantMarkup: 15, wantDiscount: 5},
+		{name: "unset", params: &EstimationParams{}, wantMarkup: 15, wantDiscount: 5},
+		{name: "overridden", params: &EstimationParams{Markup: &ten, Discount: &ten}, wantMarkup: 10, wantDiscount: 10},
+		{name: "cancelled", params: &EstimationParams{Markup: &zero, Discount: &zero}, wantMarkup: 0, wantDiscount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Markup, config.Discount = 15, 5
+
+			merged := config.WithParams(tt.params)
+			if merged.Markup != tt.wantMarkup || merged.Discount != tt.wantDiscount {
+				t.Errorf("markup/discount = %g/%g, want %g/%g", merged.Markup, merged.Discount, tt.wantMarkup, tt.wantDiscount)
+			}
+			if config.Markup != 15 || config.Discount != 5 {
+				t.Errorf("receiver modified to %g/%g", config.Markup, config.Discount)
+			}
+		})
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []ConfigOption
+		wantCategories []string
+		wantErr        string
+	}{
+		{name: "default", wantCategories: []string{"development", "project-management", "testing"}},
+		{
+			name:           "custom categories",
+			opts:           []ConfigOption{WithoutCategories(), WithCategory("design", "Design", 400), WithCategory("ops", "Ops", 0)},
+			wantCategories: []string{"design", "ops"},
+		},
+		{
+			name:    "duplicate category",
+			opts:    []ConfigOption{WithCategory("development", "Dev", 600)},
+			wantErr: "category 'development': duplicate id",
+		},
+		{
+			name:    "missing id",
+			opts:    []ConfigOption{WithCategory("", "Design", 400)},
+			wantErr: "id is required",
+		},
+		{
+			name:    "negative cost",
+			opts:    []ConfigOption{WithoutCategories(), WithCategory("design", "Design", -1)},
+			wantErr: "category 'design': cost per time unit must be >= 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewConfig(tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewConfig() error = %v", err)
+			}
+
+			if got := slices.Sorted(maps.Keys(config.TaskCategories)); !slices.Equal(got, tt.wantCategories) {
+				t.Errorf("categories = %v, want %v", got, tt.wantCategories)
+			}
+			for id, category := range config.TaskCategories {
+				if category.ID != id {
+					t.Errorf("category %q has ID %q", id, category.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestNewConfigOptions(t *testing.T) {
+	config, err := NewConfig(
+		WithTimeUnit(TimeUnit{Label: "hour", Acronym: "h"}),
+		WithCurrency("USD"),
+		WithRoundUpEstimations(false),
+	)
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+
+	if config.TimeUnit.Acronym != "h" || config.Currency != "USD" || config.RoundUpEstimations {
+		t.Errorf("NewConfig() = %+v, want the options applied", config)
+	}
+}
+
+func TestEstimationParamsAddCategory(t *testing.T) {
+	params := &EstimationParams{}
+	if err := params.AddCategory("design", "Design", 400); err != nil {
+		t.Fatalf("AddCategory() error = %v", err)
+	}
+	if got := params.TaskCategories["design"]; got.ID != "design" || got.Label != "Design" || got.CostPerTimeUnit != 400 {
+		t.Errorf("TaskCategories[design] = %+v", got)
+	}
+
+	if err := params.AddCategory("design", "Other", 100); err == nil {
+		t.Error("AddCategory() with a duplicate ID succeeded")
+	}
+	if got := params.TaskCategories["design"].Label; got != "Design" {
+		t.Errorf("duplicate AddCategory() replaced the category label with %q", got)
+	}
+}
+
+func TestWithParams(t *testing.T) {
+	roundUp := false
+	config := DefaultConfig()
+	params := &EstimationParams{
+		TaskCategories: map[string]TaskCategory{
+			"development": {Label: "Dev", CostPerTimeUnit: 700},
+			"ux":          {Label: "UX", CostPerTimeUnit: 550},
+		},
+		TimeUnit:           &TimeUnit{Label: "man-hour", Acronym: "mh"},
+		Currency:           "USD",
+		RoundUpEstimations: &roundUp,
+	}
+
+	merged := config.WithParams(params)
+
+	if got := slices.Sorted(maps.Keys(merged.TaskCategories)); !slices.Equal(got, []string{"development", "project-management", "testing", "ux"}) {
+		t.Errorf("categories = %v, want the configured ones and ux", got)
+	}
+	if got := merged.GetTaskCategory("development"); got.ID != "development" || got.CostPerTimeUnit != 700 {
+		t.Errorf("development = %+v, want the params one", got)
+	}
+	if got := merged.GetTaskCategory("ux"); got.ID != "ux" || got.Label != "UX" {
+		t.Errorf("ux = %+v, want its ID set from its key", got)
+	}
+	if merged.TimeUnit.Acronym != "mh" || merged.Currency != "USD" || merged.RoundUpEstimations {
+		t.Errorf("WithParams() = %+v, want the params time unit, currency and rounding", merged)
+	}
+
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Errorf("receiver modified to %+v", config)
+	}
+	if config.WithParams(nil) != config {
+		t.Error("WithParams(nil) didn't return the receiver")
+	}
+}
+
+func TestConfigCloneIsDeep(t *testing.T) {
+	defaultCost, trapCtrlC, showCost := 400.0, true, true
+
+	config := DefaultConfig()
+	config.TaskConfidenceFactors = map[string]float64{"low": 1.5}
+	config.DefaultCostPerTimeUnit = &defaultCost
+	config.TrapCtrlC = &trapCtrlC
+	config.ShowCost = &showCost
+	config.TaskTableColumns = []string{"label", "mean"}
+
+	want := DefaultConfig()
+	want.TaskConfidenceFactors = map[string]float64{"low": 1.5}
+	want.DefaultCostPerTimeUnit = new(float64)
+	*want.DefaultCostPerTimeUnit = defaultCost
+	want.TrapCtrlC = new(bool)
+	*want.TrapCtrlC = trapCtrlC
+	want.ShowCost = new(bool)
+	*want.ShowCost = showCost
+	want.TaskTableColumns = []string{"label", "mean"}
+
+	clone := config.Clone()
+	clone.TaskCategories["development"] = TaskCategory{ID: "development", Label: "Changed"}
+	clone.ConfidenceLevels["68%"] = 2
+	clone.TaskConfidenceFactors["low"] = 3
+	*clone.DefaultCostPerTimeUnit = 0
+	*clone.TrapCtrlC = false
+	*clone.ShowCost = false
+	clone.TaskTableColumns[0] = "cost"
+
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("modifying the clone changed the original: %+v, want %+v", config, want)
+	}
+}
@@ -1,9 +1,19 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// ErrEstimationLocked is returned when trying to modify a locked estimation
+var ErrEstimationLocked = errors.New("estimation is locked, unlock it first")
+
 // EstimationID is a unique identifier for an estimation project
 type EstimationID string
 
@@ -12,11 +22,20 @@ type Estimation struct {
 	ID          EstimationID      `yaml:"id"`
 	Label       string            `yaml:"label"`
 	Description string            `yaml:"description"`
+	Owner       string            `yaml:"owner,omitempty"`
+	TeamSize    int               `yaml:"teamSize,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Locked      bool              `yaml:"locked,omitempty"`
+	ApprovedBy  string            `yaml:"approvedBy,omitempty"`
+	ApprovedAt  *time.Time        `yaml:"approvedAt,omitempty"`
 	CreatedAt   time.Time         `yaml:"createdAt"`
 	UpdatedAt   time.Time         `yaml:"updatedAt"`
 	Ordering    []TaskID          `yaml:"ordering"`
 	Tasks       map[TaskID]*Task  `yaml:"tasks"`
 	Params      *EstimationParams `yaml:"params,omitempty"`
+
+	// Extra holds unknown keys so that they survive a load/save round-trip
+	Extra map[string]any `yaml:",inline" json:"-"`
 }
 
 // EstimationParams contains project-specific parameters that override global config
@@ -25,6 +44,11 @@ type EstimationParams struct {
 	TimeUnit           *TimeUnit               `yaml:"timeUnit,omitempty"`
 	Currency           string                  `yaml:"currency,omitempty"`
 	RoundUpEstimations *bool                   `yaml:"roundUpEstimations,omitempty"`
+
+	// Markup and Discount are percentages applied to the computed costs, the markup first.
+	// Unlike unset ones, zero values cancel the configured ones.
+	Markup   *float64 `yaml:"markup,omitempty"`
+	Discount *float64 `yaml:"discount,omitempty"`
 }
 
 // NewEstimation creates a new estimation with the given label
@@ -42,6 +66,150 @@ func NewEstimation(label string) *Estimation {
 	}
 }
 
+// AddCategory adds a task category to the parameters, see Config.AddCategory
+func (p *EstimationParams) AddCategory(id string, label string, costPerTimeUnit float64) error {
+	if p.TaskCategories == nil {
+		p.TaskCategories = make(map[string]TaskCategory)
+	}
+	return addCategory(p.TaskCategories, id, label, costPerTimeUnit)
+}
+
+// GetTeamSize returns the number of people working on the estimation,
+// defaulting to a single worker when unset
+func (e *Estimation) GetTeamSize() int {
+	if e.TeamSize <= 0 {
+		return 1
+	}
+	return e.TeamSize
+}
+
+// Clone returns a deep copy of the estimation
+func (e *Estimation) Clone() *Estimation {
+	clone := *e
+	clone.Ordering = append([]TaskID(nil), e.Ordering...)
+	clone.Tags = append([]string(nil), e.Tags...)
+
+	clone.ApprovedAt = clonePointer(e.ApprovedAt)
+	clone.Extra = cloneExtra(e.Extra)
+
+	clone.Tasks = make(map[TaskID]*Task, len(e.Tasks))
+	for id, task := range e.Tasks {
+		clone.Tasks[id] = task.Clone()
+	}
+
+	if e.Params != nil {
+		params := *e.Params
+		params.TaskCategories = maps.Clone(e.Params.TaskCategories)
+		params.TimeUnit = clonePointer(e.Params.TimeUnit)
+		params.RoundUpEstimations = clonePointer(e.Params.RoundUpEstimations)
+		params.Markup = clonePointer(e.Params.Markup)
+		params.Discount = clonePointer(e.Params.Discount)
+		clone.Params = &params
+	}
+
+	return &clone
+}
+
+// clonePointer returns a pointer to a copy of the value p points to, or nil if p is nil
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	value := *p
+	return &value
+}
+
+// cloneExtra returns a deep copy of the unknown keys of a file, as decoded from YAML
+func cloneExtra(extra map[string]any) map[string]any {
+	if extra == nil {
+		return nil
+	}
+	clone := make(map[string]any, len(extra))
+	for key, value := range extra {
+		clone[key] = cloneExtraValue(value)
+	}
+	return clone
+}
+
+// cloneExtraValue returns a deep copy of a value decoded from YAML, scalars being immutable
+func cloneExtraValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return cloneExtra(v)
+	case map[any]any:
+		clone := make(map[any]any, len(v))
+		for key, value := range v {
+			clone[key] = cloneExtraValue(value)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(v))
+		for i, value := range v {
+			clone[i] = cloneExtraValue(value)
+		}
+		return clone
+	default:
+		return value
+	}
+}
+
+// Lock locks the estimation against modifications, recording who approved it
+func (e *Estimation) Lock(approvedBy string) {
+	now := time.Now()
+	e.Locked = true
+	e.ApprovedBy = approvedBy
+	e.ApprovedAt = &now
+	e.UpdatedAt = now
+}
+
+// Unlock allows modifications of the estimation again, clearing its approval
+func (e *Estimation) Unlock() {
+	e.Locked = false
+	e.ApprovedBy = ""
+	e.ApprovedAt = nil
+	e.UpdatedAt = time.Now()
+}
+
+// CheckUnlocked returns ErrEstimationLocked if the estimation is locked
+func (e *Estimation) CheckUnlocked() error {
+	if e.Locked {
+		return ErrEstimationLocked
+	}
+	return nil
+}
+
+// HasTag returns true if the estimation is tagged with the given tag
+func (e *Estimation) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds a tag to the estimation, returning false if it is already present
+func (e *Estimation) AddTag(tag string) bool {
+	if e.HasTag(tag) {
+		return false
+	}
+	e.Tags = append(e.Tags, tag)
+	e.UpdatedAt = time.Now()
+	return true
+}
+
+// RemoveTag removes a tag from the estimation, returning false if it was not present
+func (e *Estimation) RemoveTag(tag string) bool {
+	for i, t := range e.Tags {
+		if t == tag {
+			e.Tags = append(e.Tags[:i], e.Tags[i+1:]...)
+			e.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
 // AddTask adds a new task to the estimation
 func (e *Estimation) AddTask(task *Task) {
 	e.Tasks[task.ID] = task
@@ -49,6 +217,53 @@ func (e *Estimation) AddTask(task *Task) {
 	e.UpdatedAt = time.Now()
 }
 
+// InsertTask adds a new task to the estimation at the given index of the ordering,
+// from 0 to the number of ordered tasks, returning false without adding it when the
+// index is out of range
+func (e *Estimation) InsertTask(task *Task, index int) bool {
+	if index < 0 || index > len(e.Ordering) {
+		return false
+	}
+
+	e.Tasks[task.ID] = task
+	e.Ordering = slices.Insert(e.Ordering, index, task.ID)
+	e.UpdatedAt = time.Now()
+	return true
+}
+
+// PositionOf returns the index of the task in the ordering, or -1 if it is not ordered
+func (e *Estimation) PositionOf(id TaskID) int {
+	return slices.Index(e.Ordering, id)
+}
+
+// ResolvePosition returns the ordering index designated by a task position, given as
+// a 1-based number, "before:<id>" or "after:<id>". An empty position is the end.
+func (e *Estimation) ResolvePosition(position string) (int, error) {
+	if position == "" {
+		return len(e.Ordering), nil
+	}
+
+	if where, id, ok := strings.Cut(position, ":"); ok && (where == "before" || where == "after") {
+		index := e.PositionOf(TaskID(id))
+		if index == -1 {
+			return 0, fmt.Errorf("invalid position '%s': task '%s' not found", position, id)
+		}
+		if where == "after" {
+			index++
+		}
+		return index, nil
+	}
+
+	number, err := strconv.Atoi(position)
+	if err != nil {
+		return 0, fmt.Errorf("invalid position '%s', expected a number, before:<id> or after:<id>", position)
+	}
+	if number < 1 || number > len(e.Ordering)+1 {
+		return 0, fmt.Errorf("invalid position %d, expected 1 to %d", number, len(e.Ordering)+1)
+	}
+	return number - 1, nil
+}
+
 // RemoveTask removes a task from the estimation
 func (e *Estimation) RemoveTask(id TaskID) {
 	delete(e.Tasks, id)
@@ -91,29 +306,299 @@ func (e *Estimation) MoveTask(id TaskID, offset int) bool {
 	return true
 }
 
-// GetOrderedTasks returns tasks in the specified order
+// MoveTaskToIndex moves a task to the given index of the ordering, a negative index
+// counting from the end (-1 being the last position). The index is clamped into the
+// ordering, so the move always succeeds for an ordered task. It returns the final index
+// of the task, and false if the task is not in the ordering.
+func (e *Estimation) MoveTaskToIndex(id TaskID, index int) (int, bool) {
+	currentIndex := e.PositionOf(id)
+	if currentIndex == -1 {
+		return -1, false
+	}
+
+	if index < 0 {
+		index += len(e.Ordering)
+	}
+	index = max(0, min(index, len(e.Ordering)-1))
+
+	if index != currentIndex {
+		e.Ordering = slices.Insert(slices.Delete(e.Ordering, currentIndex, currentIndex+1), index, id)
+		e.UpdatedAt = time.Now()
+	}
+	return index, true
+}
+
+// GetOrderedTasks returns tasks in the specified order. Without ordering, as for
+// estimations built in code, every task is returned sorted by ID.
 func (e *Estimation) GetOrderedTasks() []*Task {
-	tasks := make([]*Task, 0, len(e.Tasks))
+	return e.AppendOrderedTasks(make([]*Task, 0, len(e.Tasks)))
+}
+
+// AppendOrderedTasks appends the tasks in the specified order to dst and returns the
+// extended slice, allowing callers to reuse a buffer across calls (e.g. dst[:0]).
+// Without ordering, every task is appended sorted by ID.
+func (e *Estimation) AppendOrderedTasks(dst []*Task) []*Task {
+	if len(e.Ordering) == 0 {
+		start := len(dst)
+		for _, task := range e.Tasks {
+			dst = append(dst, task)
+		}
+		slices.SortFunc(dst[start:], func(a, b *Task) int { return strings.Compare(string(a.ID), string(b.ID)) })
+		return dst
+	}
+
 	for _, taskID := range e.Ordering {
 		if task, ok := e.Tasks[taskID]; ok {
-			tasks = append(tasks, task)
+			dst = append(dst, task)
+		}
+	}
+	return dst
+}
+
+// Task sort criteria
+const (
+	SortByCategory = "category"
+	SortByMean     = "mean"
+	SortByLabel    = "label"
+)
+
+// SortTasksBy reorders the tasks by category, mean (largest first) or label. The sort
+// is stable, so that ties keep their current relative order and sorting twice gives
+// the same ordering. Only the ordering is modified.
+func (e *Estimation) SortTasksBy(criterion string) error {
+	var less func(a, b *Task) bool
+	switch criterion {
+	case SortByCategory:
+		less = func(a, b *Task) bool { return a.Category < b.Category }
+	case SortByMean:
+		less = func(a, b *Task) bool { return a.WeightedMean() > b.WeightedMean() }
+	case SortByLabel:
+		less = func(a, b *Task) bool { return strings.ToLower(a.Label) < strings.ToLower(b.Label) }
+	default:
+		return fmt.Errorf("unknown sort criterion '%s', expected %s, %s or %s", criterion, SortByCategory, SortByMean, SortByLabel)
+	}
+
+	tasks := e.allOrderedTasks()
+
+	sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[i], tasks[j]) })
+
+	ordering := make([]TaskID, 0, len(tasks))
+	for _, task := range tasks {
+		ordering = append(ordering, task.ID)
+	}
+
+	if !slices.Equal(ordering, e.Ordering) {
+		e.Ordering = ordering
+		e.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// allOrderedTasks returns every task once following the ordering, its repeated entries
+// being skipped and the tasks missing from it being appended by ID to remain deterministic
+func (e *Estimation) allOrderedTasks() []*Task {
+	ordered := make(map[TaskID]bool, len(e.Tasks))
+	tasks := slices.DeleteFunc(e.GetOrderedTasks(), func(task *Task) bool {
+		if ordered[task.ID] {
+			return true
+		}
+		ordered[task.ID] = true
+		return false
+	})
+	var missing []*Task
+	for id, task := range e.Tasks {
+		if !ordered[id] {
+			missing = append(missing, task)
 		}
 	}
-	return tasks
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+
+	return append(tasks, missing...)
+}
+
+// OrderingIssues describes the inconsistencies between the tasks of an estimation and
+// their ordering, as left by hand edits or other tools
+type OrderingIssues struct {
+	// Unordered are the tasks missing from the ordering, sorted by ID
+	Unordered []TaskID
+	// Dangling are the ordering entries without task
+	Dangling []TaskID
+	// Duplicates are the ordering entries listed more than once
+	Duplicates []TaskID
+}
+
+// IsEmpty returns true if the ordering has no issue
+func (i OrderingIssues) IsEmpty() bool {
+	return len(i.Unordered) == 0 && len(i.Dangling) == 0 && len(i.Duplicates) == 0
+}
+
+// CheckOrdering returns the inconsistencies between the tasks and their ordering
+func (e *Estimation) CheckOrdering() OrderingIssues {
+	var issues OrderingIssues
+
+	seen := make(map[TaskID]bool, len(e.Ordering))
+	for _, id := range e.Ordering {
+		switch {
+		case seen[id]:
+			if !slices.Contains(issues.Duplicates, id) {
+				issues.Duplicates = append(issues.Duplicates, id)
+			}
+		case e.Tasks[id] == nil:
+			issues.Dangling = append(issues.Dangling, id)
+		}
+		seen[id] = true
+	}
+
+	for id := range e.Tasks {
+		if !seen[id] {
+			issues.Unordered = append(issues.Unordered, id)
+		}
+	}
+	slices.Sort(issues.Unordered)
+
+	return issues
+}
+
+// NormalizeOrdering repairs the ordering, removing the dangling entries and the repeated
+// ones (the first being kept) and appending the unordered tasks sorted by ID. It returns
+// the repaired issues.
+func (e *Estimation) NormalizeOrdering() OrderingIssues {
+	issues := e.CheckOrdering()
+	if issues.IsEmpty() {
+		return issues
+	}
+
+	seen := make(map[TaskID]bool, len(e.Ordering))
+	ordering := make([]TaskID, 0, len(e.Tasks))
+	for _, id := range e.Ordering {
+		if !seen[id] && e.Tasks[id] != nil {
+			ordering = append(ordering, id)
+		}
+		seen[id] = true
+	}
+	e.Ordering = append(ordering, issues.Unordered...)
+	e.UpdatedAt = time.Now()
+
+	return issues
+}
+
+// InitOrdering populates an empty ordering from the tasks sorted by ID, as for files
+// written by other tools without one, and returns true if it did
+func (e *Estimation) InitOrdering() bool {
+	if len(e.Ordering) > 0 || len(e.Tasks) == 0 {
+		return false
+	}
+
+	e.Ordering = make([]TaskID, 0, len(e.Tasks))
+	for _, task := range e.allOrderedTasks() {
+		e.Ordering = append(e.Ordering, task.ID)
+	}
+
+	return true
+}
+
+// RenumberTasks reassigns sequential IDs (t1, t2, ...) to the tasks following the
+// ordering, and returns the new ID of each renumbered task keyed by its old ID. The
+// ordering is rewritten too, without its dangling or repeated entries and with the
+// unordered tasks, even if no ID changes.
+func (e *Estimation) RenumberTasks() map[TaskID]TaskID {
+	tasks := e.allOrderedTasks()
+
+	renamed := make(map[TaskID]TaskID)
+	renumbered := make(map[TaskID]*Task, len(tasks))
+	ordering := make([]TaskID, 0, len(tasks))
+
+	for i, task := range tasks {
+		id := TaskID(fmt.Sprintf("t%d", i+1))
+		if task.ID != id {
+			renamed[task.ID] = id
+			task.ID = id
+		}
+		renumbered[id] = task
+		ordering = append(ordering, id)
+	}
+
+	e.Tasks = renumbered
+	if len(renamed) > 0 || !slices.Equal(e.Ordering, ordering) {
+		e.UpdatedAt = time.Now()
+	}
+	e.Ordering = ordering
+
+	return renamed
 }
 
 // UpdateTask updates an existing task
 func (e *Estimation) UpdateTask(task *Task) {
 	if _, ok := e.Tasks[task.ID]; ok {
+		task.Touch()
 		e.Tasks[task.ID] = task
-		e.UpdatedAt = time.Now()
+		e.UpdatedAt = task.UpdatedAt
+	}
+}
+
+// Warnings returns the heuristic warnings of every task, using the thresholds of the given configuration
+func (e *Estimation) Warnings(config *Config) []string {
+	var warnings []string
+
+	for _, task := range e.GetOrderedTasks() {
+		for _, warning := range task.Warnings(config.GetWideRangeRatio(), config.GetNarrowRangeThreshold()) {
+			warnings = append(warnings, "task "+string(task.ID)+" ("+task.Label+"): "+warning)
+		}
+		if !config.HasTaskCategory(task.Category) {
+			warnings = append(warnings, fmt.Sprintf("task %s (%s): unknown category '%s', costed at the default %g per time unit",
+				task.ID, task.Label, task.Category, config.GetDefaultCostPerTimeUnit()))
+		}
 	}
+
+	for _, duplicates := range e.DuplicateTasks() {
+		ids := make([]string, 0, len(duplicates))
+		for _, task := range duplicates {
+			ids = append(ids, string(task.ID))
+		}
+		warnings = append(warnings, "duplicate label '"+strings.TrimSpace(duplicates[0].Label)+"': tasks "+strings.Join(ids, ", "))
+	}
+
+	return warnings
+}
+
+// DuplicateTasks returns the groups of tasks sharing the same label, compared
+// trimmed and case-insensitively. Groups and tasks follow the task ordering.
+func (e *Estimation) DuplicateTasks() [][]*Task {
+	groups := make(map[string][]*Task)
+	var keys []string
+
+	for _, task := range e.GetOrderedTasks() {
+		key := strings.ToLower(strings.TrimSpace(task.Label))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], task)
+	}
+
+	var duplicates [][]*Task
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, groups[key])
+		}
+	}
+
+	return duplicates
 }
 
 // Validate validates the entire estimation
 func (e *Estimation) Validate() []string {
 	var errors []string
 
+	if e.Params != nil {
+		if e.Params.Markup != nil && *e.Params.Markup < 0 {
+			errors = append(errors, "params: markup must be >= 0")
+		}
+		if e.Params.Discount != nil && (*e.Params.Discount < 0 || *e.Params.Discount > 100) {
+			errors = append(errors, "params: discount must be between 0 and 100")
+		}
+	}
+
 	for _, task := range e.Tasks {
 		if taskErrors := task.Validate(); len(taskErrors) > 0 {
 			for _, err := range taskErrors {
@@ -0,0 +1,199 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// newOrderedEstimation returns an estimation of n tasks, ordered or not
+func newOrderedEstimation(n int, ordered bool) *Estimation {
+	estimation := NewEstimation("ordered")
+	for i := range n {
+		task := NewTask(fmt.Sprintf("Task %d", i), "development")
+		task.ID = TaskID(fmt.Sprintf("t%05d", i))
+		estimation.AddTask(task)
+	}
+	if !ordered {
+		estimation.Ordering = nil
+	}
+	return estimation
+}
+
+// BenchmarkOrderedTasks compares allocating the ordered tasks on every call with
+// appending them to a reused buffer, as the TUI does on every refresh
+func BenchmarkOrderedTasks(b *testing.B) {
+	for _, ordered := range []bool{true, false} {
+		estimation := newOrderedEstimation(1000, ordered)
+		name := "ordered"
+		if !ordered {
+			name = "unordered"
+		}
+
+		b.Run("get/"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = estimation.GetOrderedTasks()
+			}
+		})
+
+		b.Run("append/"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf []*Task
+			for b.Loop() {
+				buf = estimation.AppendOrderedTasks(buf[:0])
+			}
+		})
+	}
+}
+
+func TestAppendOrderedTasksMatchesGetOrderedTasks(t *testing.T) {
+	for _, ordered := range []bool{true, false} {
+		estimation := newOrderedEstimation(50, ordered)
+
+		want := estimation.GetOrderedTasks()
+		buf := make([]*Task, 0, 8)
+		for range 2 {
+			buf = estimation.AppendOrderedTasks(buf[:0])
+			if len(buf) != len(want) {
+				t.Fatalf("ordered=%v: got %d tasks, want %d", ordered, len(buf), len(want))
+			}
+			for i := range want {
+				if buf[i] != want[i] {
+					t.Fatalf("ordered=%v: task %d = %s, want %s", ordered, i, buf[i].ID, want[i].ID)
+				}
+			}
+		}
+	}
+}
+
+func TestRenumberTasks(t *testing.T) {
+	newEstimation := func(ids []TaskID, ordering []TaskID) *Estimation {
+		estimation := NewEstimation("renumber")
+		estimation.UpdatedAt = estimation.UpdatedAt.Add(-time.Hour)
+		for _, id := range ids {
+			task := NewTask(string(id), "development")
+			task.ID = id
+			estimation.Tasks[id] = task
+		}
+		estimation.Ordering = ordering
+		return estimation
+	}
+
+	tests := []struct {
+		name         string
+		ids          []TaskID
+		ordering     []TaskID
+		wantRenamed  map[TaskID]TaskID
+		wantLabels   []string
+		wantModified bool
+	}{
+		{
+			name:         "unordered task",
+			ids:          []TaskID{"t1", "t2", "x"},
+			ordering:     []TaskID{"t1", "t2"},
+			wantRenamed:  map[TaskID]TaskID{"x": "t3"},
+			wantLabels:   []string{"t1", "t2", "x"},
+			wantModified: true,
+		},
+		{
+			name:         "repeated and dangling entries",
+			ids:          []TaskID{"t1", "t2", "x"},
+			ordering:     []TaskID{"t2", "gone", "t1", "t2"},
+			wantRenamed:  map[TaskID]TaskID{"t2": "t1", "t1": "t2", "x": "t3"},
+			wantLabels:   []string{"t2", "t1", "x"},
+			wantModified: true,
+		},
+		{
+			name:         "ordering only",
+			ids:          []TaskID{"t1", "t2"},
+			ordering:     []TaskID{"t1", "gone", "t2", "t1"},
+			wantRenamed:  map[TaskID]TaskID{},
+			wantLabels:   []string{"t1", "t2"},
+			wantModified: true,
+		},
+		{
+			name:        "unchanged",
+			ids:         []TaskID{"t1", "t2"},
+			ordering:    []TaskID{"t1", "t2"},
+			wantRenamed: map[TaskID]TaskID{},
+			wantLabels:  []string{"t1", "t2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimation := newEstimation(tt.ids, tt.ordering)
+			updatedAt := estimation.UpdatedAt
+
+			renamed := estimation.RenumberTasks()
+			if fmt.Sprint(renamed) != fmt.Sprint(tt.wantRenamed) {
+				t.Errorf("renamed = %v, want %v", renamed, tt.wantRenamed)
+			}
+			if modified := !estimation.UpdatedAt.Equal(updatedAt); modified != tt.wantModified {
+				t.Errorf("modified = %v, want %v", modified, tt.wantModified)
+			}
+
+			if len(estimation.Tasks) != len(tt.ids) || len(estimation.Ordering) != len(tt.ids) {
+				t.Fatalf("%d tasks ordered %v, want %d", len(estimation.Tasks), estimation.Ordering, len(tt.ids))
+			}
+			for i, task := range estimation.GetOrderedTasks() {
+				wantID := TaskID(fmt.Sprintf("t%d", i+1))
+				if task.ID != wantID || estimation.Ordering[i] != wantID || task.Label != tt.wantLabels[i] {
+					t.Errorf("task %d = %s (%s), want %s (%s)", i, task.ID, task.Label, wantID, tt.wantLabels[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCloneIsDeep(t *testing.T) {
+	roundUp, markup, discount, maxEstimate := true, 10.0, 5.0, 8.0
+
+	estimation := NewEstimation("Project")
+	estimation.Tags = []string{"q1"}
+	estimation.Lock("alice")
+	estimation.Extra = map[string]any{"custom": map[string]any{"list": []any{"a", map[string]any{"b": 1}}}}
+	estimation.Params = &EstimationParams{
+		TaskCategories:     map[string]TaskCategory{"design": {ID: "design", Label: "Design", CostPerTimeUnit: 400}},
+		TimeUnit:           &TimeUnit{Label: "hour", Acronym: "h"},
+		RoundUpEstimations: &roundUp,
+		Markup:             &markup,
+		Discount:           &discount,
+	}
+	task := NewTask("Task", "design")
+	task.MaxEstimate = &maxEstimate
+	task.Tags = []string{"backend"}
+	task.Extra = map[string]any{"custom": []any{1, 2}}
+	estimation.AddTask(task)
+
+	want, err := yaml.Marshal(estimation)
+	if err != nil {
+		t.Fatalf("failed to marshal estimation: %v", err)
+	}
+
+	clone := estimation.Clone()
+	*clone.ApprovedAt = clone.ApprovedAt.Add(time.Hour)
+	clone.Tags[0] = "q2"
+	clone.Extra["custom"].(map[string]any)["list"].([]any)[1].(map[string]any)["b"] = 2
+	clone.Params.TaskCategories["design"] = TaskCategory{ID: "design", Label: "Changed"}
+	clone.Params.TimeUnit.Acronym = "d"
+	*clone.Params.RoundUpEstimations = false
+	*clone.Params.Markup = 0
+	*clone.Params.Discount = 0
+	clonedTask := clone.Tasks[task.ID]
+	*clonedTask.MaxEstimate = 1
+	clonedTask.Tags[0] = "frontend"
+	clonedTask.Extra["custom"].([]any)[0] = 3
+	clonedTask.Label = "Changed"
+
+	got, err := yaml.Marshal(estimation)
+	if err != nil {
+		t.Fatalf("failed to marshal estimation: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("modifying the clone changed the original:\n%s\nwant:\n%s", got, want)
+	}
+}
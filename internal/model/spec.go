@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// EstimationSpec describes a fully-populated estimation to create at once, e.g. with
+// `new --from` or the create_estimation MCP tool
+type EstimationSpec struct {
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Owner       string                  `json:"owner,omitempty" yaml:"owner,omitempty"`
+	TeamSize    int                     `json:"teamSize,omitempty" yaml:"teamSize,omitempty"`
+	Tags        []string                `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Categories  map[string]CategorySpec `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Tasks       []TaskSpec              `json:"tasks" yaml:"tasks"`
+}
+
+// CategorySpec describes an estimation-specific task category
+type CategorySpec struct {
+	Label           string  `json:"label" yaml:"label"`
+	CostPerTimeUnit float64 `json:"costPerTimeUnit" yaml:"costPerTimeUnit"`
+}
+
+// TaskSpec describes a task to create
+type TaskSpec struct {
+	Label       string  `json:"label" yaml:"label"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Category    string  `json:"category,omitempty" yaml:"category,omitempty"`
+	Assignee    string  `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	Optimistic  float64 `json:"optimistic,omitempty" yaml:"optimistic,omitempty"`
+	Likely      float64 `json:"likely,omitempty" yaml:"likely,omitempty"`
+	Pessimistic float64 `json:"pessimistic,omitempty" yaml:"pessimistic,omitempty"`
+	Confidence  string  `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+	FixedCost   float64 `json:"fixedCost,omitempty" yaml:"fixedCost,omitempty"`
+}
+
+// NewEstimationFromSpec builds a new estimation from the given spec. The categories of the
+// spec are added to the configured ones, and the tasks without category get the default one
+// of the merged configuration. Missing estimates are auto-filled as with Task.SetEstimations.
+// All categories and tasks are validated and every failure is reported in the returned error.
+func NewEstimationFromSpec(label string, spec *EstimationSpec, config *Config) (*Estimation, error) {
+	estimation := NewEstimation(label)
+	estimation.Description = spec.Description
+	estimation.Owner = spec.Owner
+	estimation.TeamSize = spec.TeamSize
+	estimation.Tags = spec.Tags
+
+	var failures []string
+
+	if spec.TeamSize < 0 {
+		failures = append(failures, "team size must be >= 0")
+	}
+
+	if len(spec.Categories) > 0 {
+		params := &EstimationParams{}
+		for _, id := range slices.Sorted(maps.Keys(spec.Categories)) {
+			cat := spec.Categories[id]
+			if err := params.AddCategory(id, cat.Label, cat.CostPerTimeUnit); err != nil {
+				failures = append(failures, fmt.Sprintf("category '%s': %s", id, err))
+			}
+		}
+		estimation.Params = params
+	}
+
+	config = config.WithParams(estimation.Params)
+
+	for i, taskSpec := range spec.Tasks {
+		name := fmt.Sprintf("task #%d", i+1)
+		if taskSpec.Label != "" {
+			name = fmt.Sprintf("task #%d '%s'", i+1, taskSpec.Label)
+		}
+
+		var errors []string
+		if strings.TrimSpace(taskSpec.Label) == "" {
+			errors = append(errors, "label is required")
+		}
+
+		category := taskSpec.Category
+		if category == "" {
+			var err error
+			if category, err = config.GetDefaultCategoryID(); err != nil {
+				errors = append(errors, err.Error())
+			}
+		} else if !config.HasTaskCategory(category) {
+			errors = append(errors, fmt.Sprintf("unknown category '%s'", category))
+		}
+
+		if taskSpec.Confidence != "" && !config.HasTaskConfidence(taskSpec.Confidence) {
+			errors = append(errors, fmt.Sprintf("invalid confidence level '%s', expected one of: %s", taskSpec.Confidence, strings.Join(config.GetTaskConfidenceLevels(), ", ")))
+		}
+
+		if taskSpec.Optimistic < 0 || taskSpec.Likely < 0 || taskSpec.Pessimistic < 0 {
+			errors = append(errors, "estimates must be >= 0")
+		}
+
+		if len(errors) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, strings.Join(errors, ", ")))
+			continue
+		}
+
+		task := NewTask(taskSpec.Label, category)
+		task.Description = taskSpec.Description
+		task.Assignee = taskSpec.Assignee
+		task.Confidence = taskSpec.Confidence
+		task.FixedCost = taskSpec.FixedCost
+		task.SetEstimations(taskSpec.Optimistic, taskSpec.Likely, taskSpec.Pessimistic, config.GetAutoEstimationMultiplier())
+
+		if taskErrors := task.Validate(); len(taskErrors) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, strings.Join(taskErrors, ", ")))
+			continue
+		}
+
+		estimation.AddTask(task)
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("invalid spec, %d error(s):\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+
+	return estimation, nil
+}
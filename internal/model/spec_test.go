@@ -0,0 +1,66 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewEstimationFromSpec(t *testing.T) {
+	config := DefaultConfig()
+	config.TaskCategories = map[string]TaskCategory{"development": {ID: "development", Label: "Development", CostPerTimeUnit: 500}}
+
+	spec := &EstimationSpec{
+		Categories: map[string]CategorySpec{"ux": {Label: "UX", CostPerTimeUnit: 550}},
+		Tasks: []TaskSpec{
+			{Label: "Mockups", Category: "ux", Likely: 2, Confidence: TaskConfidenceLow},
+			{Label: "License", Likely: 1, FixedCost: 1200},
+		},
+	}
+
+	estimation, err := NewEstimationFromSpec("Project", spec, config)
+	if err != nil {
+		t.Fatalf("NewEstimationFromSpec() error = %v", err)
+	}
+
+	tasks := estimation.GetOrderedTasks()
+	if len(tasks) != 2 {
+		t.Fatalf("tasks = %d, want 2", len(tasks))
+	}
+	if tasks[0].Category != "ux" || tasks[0].Confidence != TaskConfidenceLow {
+		t.Errorf("task #1 = %s/%s, want ux/low", tasks[0].Category, tasks[0].Confidence)
+	}
+	// The spec categories are added to the configured ones, so that the default one is kept
+	if tasks[1].Category != "development" || tasks[1].FixedCost != 1200 {
+		t.Errorf("task #2 = %s/%v, want development/1200", tasks[1].Category, tasks[1].FixedCost)
+	}
+}
+
+func TestNewEstimationFromSpecReportsEveryFailure(t *testing.T) {
+	spec := &EstimationSpec{
+		TeamSize: -1,
+		Tasks: []TaskSpec{
+			{Label: "Unknown", Category: "typo", Likely: 2},
+			{Label: "Unsure", Likely: 2, Confidence: "maybe"},
+			{Label: " ", Likely: 2},
+			{Label: "Costly", Likely: 2, FixedCost: -5},
+		},
+	}
+
+	_, err := NewEstimationFromSpec("Project", spec, DefaultConfig())
+	if err == nil {
+		t.Fatal("NewEstimationFromSpec() error = nil, want an error")
+	}
+
+	for _, want := range []string{
+		"5 error(s)",
+		"team size must be >= 0",
+		"task #1 'Unknown': unknown category 'typo'",
+		"task #2 'Unsure': invalid confidence level 'maybe'",
+		"task #3 ' ': label is required",
+		"task #4 'Costly': fixed cost must be >= 0",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want %q", err, want)
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package model
 
 import (
+	"fmt"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,10 +20,43 @@ type Task struct {
 	Label       string      `yaml:"label"`
 	Description string      `yaml:"description,omitempty"`
 	Category    string      `yaml:"category"`
+	Assignee    string      `yaml:"assignee,omitempty"`
 	Estimations Estimations `yaml:"estimations"`
+
+	// MaxEstimate caps the estimate of a time-boxed task
+	MaxEstimate *float64 `yaml:"maxEstimate,omitempty"`
+
+	// Confidence is the stability level of the task requirements (e.g. high, medium or low),
+	// inflating its standard deviation in the project variance
+	Confidence string `yaml:"confidence,omitempty"`
+
+	// FixedCost is a certain cost unrelated to the effort (e.g. a license), added to the
+	// project costs without affecting the time estimates
+	FixedCost float64 `yaml:"fixedCost,omitempty"`
+
+	// Tags are free-form labels (e.g. must-have), a task having any number of them
+	Tags []string `yaml:"tags,omitempty"`
+
+	// CreatedAt and UpdatedAt are zero for tasks saved before they were tracked
+	CreatedAt time.Time `yaml:"createdAt,omitempty"`
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty"`
+
+	// Extra holds unknown keys so that they survive a load/save round-trip
+	Extra map[string]any `yaml:",inline" json:"-"`
+}
+
+// Clone returns a deep copy of the task
+func (t *Task) Clone() *Task {
+	clone := *t
+	clone.MaxEstimate = clonePointer(t.MaxEstimate)
+	clone.Tags = append([]string(nil), t.Tags...)
+	clone.Extra = cloneExtra(t.Extra)
+	return &clone
 }
 
-// Estimations contains the 3-point estimation values
+// Estimations contains the 3-point estimation values. They are stored as entered: the
+// rounding configuration only applies to the displayed values (see Config.DisplayEstimations),
+// so that a saved estimation is loaded back with the exact input values.
 type Estimations struct {
 	Optimistic  float64 `yaml:"optimistic"`
 	Likely      float64 `yaml:"likely"`
@@ -27,6 +65,7 @@ type Estimations struct {
 
 // NewTask creates a new task with the given label and category
 func NewTask(label, category string) *Task {
+	now := time.Now()
 	return &Task{
 		ID:          TaskID(generateID()),
 		Label:       label,
@@ -37,19 +76,103 @@ func NewTask(label, category string) *Task {
 			Likely:      0,
 			Pessimistic: 0,
 		},
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 }
 
+// Touch records the task as modified now
+func (t *Task) Touch() {
+	t.UpdatedAt = time.Now()
+}
+
+// Merge adds the estimates, fixed cost and tags of another task to the task, e.g. a
+// duplicate of it. The caps are summed when both tasks have one, and dropped otherwise as
+// the uncapped part is unbounded.
+func (t *Task) Merge(other *Task) {
+	t.Estimations.Optimistic += other.Estimations.Optimistic
+	t.Estimations.Likely += other.Estimations.Likely
+	t.Estimations.Pessimistic += other.Estimations.Pessimistic
+
+	if t.MaxEstimate != nil && other.MaxEstimate != nil {
+		maxEstimate := *t.MaxEstimate + *other.MaxEstimate
+		t.MaxEstimate = &maxEstimate
+	} else {
+		t.MaxEstimate = nil
+	}
+
+	t.FixedCost += other.FixedCost
+
+	for _, tag := range other.Tags {
+		if !slices.Contains(t.Tags, tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+
+	t.Touch()
+}
+
+// Scale multiplies the estimates and the cap of the task by a positive factor, rounded to
+// the hundredth to avoid floating point noise. It reports whether any value changed, the
+// task being only touched then.
+func (t *Task) Scale(factor float64) bool {
+	scaled := Estimations{
+		Optimistic:  scaleValue(t.Estimations.Optimistic, factor),
+		Likely:      scaleValue(t.Estimations.Likely, factor),
+		Pessimistic: scaleValue(t.Estimations.Pessimistic, factor),
+	}
+	changed := scaled != t.Estimations
+	t.Estimations = scaled
+
+	if t.MaxEstimate != nil {
+		maxEstimate := scaleValue(*t.MaxEstimate, factor)
+		changed = changed || maxEstimate != *t.MaxEstimate
+		t.MaxEstimate = &maxEstimate
+	}
+
+	if changed {
+		t.Touch()
+	}
+	return changed
+}
+
+// scaleValue multiplies a value by the factor, rounded to the hundredth
+func scaleValue(value float64, factor float64) float64 {
+	return math.Round(value*factor*100) / 100
+}
+
 // WeightedMean calculates the weighted mean (expected value) using the 3-point estimation formula
-// E = (O + 4*L + P) / 6
+// E = (O + 4*L + P) / 6, clamped at the task cap if any
 func (t *Task) WeightedMean() float64 {
-	return (t.Estimations.Optimistic + 4*t.Estimations.Likely + t.Estimations.Pessimistic) / 6
+	mean := (t.Estimations.Optimistic + 4*t.Estimations.Likely + t.Estimations.Pessimistic) / 6
+	if t.MaxEstimate != nil {
+		return math.Min(mean, *t.MaxEstimate)
+	}
+	return mean
 }
 
 // StandardDeviation calculates the standard deviation using the 3-point estimation formula
-// SD = (P - O) / 6
+// SD = (P - O) / 6, with the estimates clamped at the task cap if any
 func (t *Task) StandardDeviation() float64 {
-	return (t.Estimations.Pessimistic - t.Estimations.Optimistic) / 6
+	return t.StandardDeviationWith(EstimationModelPERT6Sigma)
+}
+
+// StandardDeviationWith calculates the standard deviation using the given estimation model
+// (see EstimationModelPERT6Sigma and EstimationModelPERTP10P90), with the estimates
+// clamped at the task cap if any. Unknown models fall back to EstimationModelPERT6Sigma.
+func (t *Task) StandardDeviationWith(estimationModel string) float64 {
+	optimistic, pessimistic := t.Estimations.Optimistic, t.Estimations.Pessimistic
+	if t.MaxEstimate != nil {
+		optimistic = math.Min(optimistic, *t.MaxEstimate)
+		pessimistic = math.Min(pessimistic, *t.MaxEstimate)
+	}
+
+	return (pessimistic - optimistic) / StandardDeviationDivisor(estimationModel)
+}
+
+// IsCapped returns true if the task cap reduces its estimates
+func (t *Task) IsCapped() bool {
+	return t.MaxEstimate != nil && t.Estimations.Pessimistic > *t.MaxEstimate
 }
 
 // Validate checks if the task estimations are valid (optimistic <= likely <= pessimistic)
@@ -73,9 +196,35 @@ func (t *Task) Validate() []string {
 		errors = append(errors, "pessimistic estimate should be >= likely estimate")
 	}
 
+	if t.MaxEstimate != nil && *t.MaxEstimate <= 0 {
+		errors = append(errors, "max estimate must be > 0")
+	}
+
+	if t.FixedCost < 0 {
+		errors = append(errors, "fixed cost must be >= 0")
+	}
+
 	return errors
 }
 
+// Warnings checks the task estimations for likely data-entry mistakes: a pessimistic
+// estimate more than wideRatio times the optimistic one, or equal estimates (no
+// uncertainty) above narrowThreshold. A negative threshold disables the matching check.
+func (t *Task) Warnings(wideRatio, narrowThreshold float64) []string {
+	var warnings []string
+
+	e := t.Estimations
+
+	if wideRatio >= 0 && e.Optimistic > 0 && e.Pessimistic > e.Optimistic*wideRatio {
+		warnings = append(warnings, fmt.Sprintf("pessimistic estimate is more than %gx the optimistic one (suspiciously wide range)", wideRatio))
+	}
+	if narrowThreshold >= 0 && e.Optimistic == e.Likely && e.Likely == e.Pessimistic && e.Likely > narrowThreshold {
+		warnings = append(warnings, "optimistic, likely and pessimistic estimates are equal (no uncertainty)")
+	}
+
+	return warnings
+}
+
 // SetEstimations sets all three estimates and ensures coherency using the given multiplier.
 // The multiplier determines the percentage difference between adjacent estimates.
 // Missing values (0) are auto-filled, and constraints are enforced by propagating forward:
@@ -137,6 +286,38 @@ func (t *Task) SetEstimations(optimistic, likely, pessimistic float64, multiplie
 	t.Estimations.Optimistic = o
 	t.Estimations.Likely = l
 	t.Estimations.Pessimistic = p
+	t.Touch()
+}
+
+// ParseRange parses an estimate range formatted as "min-max" (e.g. "3-8") and
+// returns its optimistic and pessimistic bounds
+func ParseRange(s string) (float64, float64, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range '%s': expected format min-max (e.g. 3-8)", s)
+	}
+
+	low, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range '%s': expected format min-max (e.g. 3-8), min is not a number", s)
+	}
+
+	high, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range '%s': expected format min-max (e.g. 3-8), max is not a number", s)
+	}
+
+	if low > high {
+		return 0, 0, fmt.Errorf("invalid range '%s': min must be <= max", s)
+	}
+
+	return low, high, nil
+}
+
+// SetRange sets the estimations from an optimistic-pessimistic range, using
+// the midpoint as likely estimate
+func (t *Task) SetRange(optimistic, pessimistic float64, multiplier float64) {
+	t.SetEstimations(optimistic, (optimistic+pessimistic)/2, pessimistic, multiplier)
 }
 
 func generateID() string {
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskMerge(t *testing.T) {
+	newTask := func(maxEstimate *float64, fixedCost float64, tags ...string) *Task {
+		task := NewTask("Login", "development")
+		task.Estimations = Estimations{Optimistic: 1, Likely: 2, Pessimistic: 4}
+		task.MaxEstimate = maxEstimate
+		task.FixedCost = fixedCost
+		task.Tags = tags
+		task.UpdatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		return task
+	}
+	capAt := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name            string
+		task, duplicate *Task
+		wantMaxEstimate *float64
+		wantFixedCost   float64
+		wantTags        []string
+	}{
+		{
+			name:          "uncapped",
+			task:          newTask(nil, 100, "backend"),
+			duplicate:     newTask(nil, 50, "backend", "q1"),
+			wantFixedCost: 150,
+			wantTags:      []string{"backend", "q1"},
+		},
+		{
+			name:            "both capped",
+			task:            newTask(capAt(3), 0),
+			duplicate:       newTask(capAt(2), 0, "q1"),
+			wantMaxEstimate: capAt(5),
+			wantTags:        []string{"q1"},
+		},
+		{
+			name:      "one capped",
+			task:      newTask(capAt(3), 0),
+			duplicate: newTask(nil, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.task.UpdatedAt
+			tt.task.Merge(tt.duplicate)
+
+			if want := (Estimations{Optimistic: 2, Likely: 4, Pessimistic: 8}); tt.task.Estimations != want {
+				t.Errorf("estimations = %+v, want %+v", tt.task.Estimations, want)
+			}
+			if !reflect.DeepEqual(tt.task.MaxEstimate, tt.wantMaxEstimate) {
+				t.Errorf("max estimate = %v, want %v", formatCap(tt.task.MaxEstimate), formatCap(tt.wantMaxEstimate))
+			}
+			if tt.task.FixedCost != tt.wantFixedCost {
+				t.Errorf("fixed cost = %v, want %v", tt.task.FixedCost, tt.wantFixedCost)
+			}
+			if !reflect.DeepEqual(tt.task.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tt.task.Tags, tt.wantTags)
+			}
+			if !tt.task.UpdatedAt.After(before) {
+				t.Errorf("updatedAt = %v, want it touched", tt.task.UpdatedAt)
+			}
+		})
+	}
+}
+
+// formatCap formats an optional task cap for the test errors
+func formatCap(maxEstimate *float64) any {
+	if maxEstimate == nil {
+		return nil
+	}
+	return *maxEstimate
+}
+
+func TestTaskScale(t *testing.T) {
+	capAt := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name            string
+		estimations     Estimations
+		maxEstimate     *float64
+		factor          float64
+		want            Estimations
+		wantMaxEstimate *float64
+		wantChanged     bool
+	}{
+		{
+			name:        "zero optimistic",
+			estimations: Estimations{Optimistic: 0, Likely: 3, Pessimistic: 4},
+			factor:      1.5,
+			want:        Estimations{Optimistic: 0, Likely: 4.5, Pessimistic: 6},
+			wantChanged: true,
+		},
+		{
+			name:        "equal bounds",
+			estimations: Estimations{Optimistic: 2, Likely: 2, Pessimistic: 2},
+			factor:      1.3,
+			want:        Estimations{Optimistic: 2.6, Likely: 2.6, Pessimistic: 2.6},
+			wantChanged: true,
+		},
+		{
+			name:            "capped",
+			estimations:     Estimations{Optimistic: 1, Likely: 2, Pessimistic: 4},
+			maxEstimate:     capAt(3),
+			factor:          0.5,
+			want:            Estimations{Optimistic: 0.5, Likely: 1, Pessimistic: 2},
+			wantMaxEstimate: capAt(1.5),
+			wantChanged:     true,
+		},
+		{
+			name:            "factor of one",
+			estimations:     Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+			maxEstimate:     capAt(3),
+			factor:          1,
+			want:            Estimations{Optimistic: 5, Likely: 2, Pessimistic: 3},
+			wantMaxEstimate: capAt(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask("Login", "development")
+			task.Estimations = tt.estimations
+			task.MaxEstimate = tt.maxEstimate
+			before := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			task.UpdatedAt = before
+
+			if changed := task.Scale(tt.factor); changed != tt.wantChanged {
+				t.Errorf("Scale() = %v, want %v", changed, tt.wantChanged)
+			}
+			if task.Estimations != tt.want {
+				t.Errorf("estimations = %+v, want %+v", task.Estimations, tt.want)
+			}
+			if !reflect.DeepEqual(task.MaxEstimate, tt.wantMaxEstimate) {
+				t.Errorf("max estimate = %v, want %v", formatCap(task.MaxEstimate), formatCap(tt.wantMaxEstimate))
+			}
+			if touched := task.UpdatedAt.After(before); touched != tt.wantChanged {
+				t.Errorf("touched = %v, want %v", touched, tt.wantChanged)
+			}
+		})
+	}
+}
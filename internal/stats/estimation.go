@@ -1,7 +1,14 @@
 package stats
 
 import (
+	"fmt"
+	"maps"
 	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/bornholm/guesstimate/internal/model"
 )
@@ -12,6 +19,40 @@ type EstimationResult struct {
 	StandardDeviation float64 `json:"standardDeviation"`
 }
 
+// IsEmpty reports whether nothing has been estimated yet, either because there
+// are no tasks or because all their estimates are zero
+func (r EstimationResult) IsEmpty() bool {
+	return r.WeightedMean == 0 && r.StandardDeviation == 0
+}
+
+// Scale returns the estimation result with its mean and standard deviation multiplied by factor
+func (r EstimationResult) Scale(factor float64) EstimationResult {
+	return EstimationResult{
+		WeightedMean:      r.WeightedMean * factor,
+		StandardDeviation: r.StandardDeviation * factor,
+	}
+}
+
+// ZScore returns the number of standard deviations between the mean and the given value,
+// positive above the mean. A deterministic result (no standard deviation) gives ±Inf,
+// or 0 for its mean.
+func (r EstimationResult) ZScore(value float64) float64 {
+	diff := value - r.WeightedMean
+	if r.StandardDeviation == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return math.Inf(int(math.Copysign(1, diff)))
+	}
+	return diff / r.StandardDeviation
+}
+
+// Probability returns the probability that the total doesn't exceed the given value,
+// using the normal approximation of the estimation result
+func (r EstimationResult) Probability(value float64) float64 {
+	return 0.5 * (1 + math.Erf(r.ZScore(value)/math.Sqrt2))
+}
+
 // ConfidenceLevel represents a confidence level with its multiplier
 type ConfidenceLevel struct {
 	Name       string
@@ -25,6 +66,88 @@ var (
 	Confidence997 = ConfidenceLevel{Name: "99.7%", Multiplier: 3}
 )
 
+// DefaultConfidenceLevels lists the built-in confidence levels, from widest to narrowest
+var DefaultConfidenceLevels = []ConfidenceLevel{Confidence997, Confidence90, Confidence68}
+
+// ResolveConfidenceLevel returns the given confidence level with its multiplier
+// overridden by the configuration, falling back to the built-in value when the
+// configured one is missing or invalid
+func ResolveConfidenceLevel(config *model.Config, level ConfidenceLevel) ConfidenceLevel {
+	if multiplier, ok := config.ConfidenceLevels[level.Name]; ok && multiplier > 0 {
+		level.Multiplier = multiplier
+	}
+	return level
+}
+
+// GetConfidenceLevels returns the built-in confidence levels merged with the
+// additional ones from the configuration, sorted from widest to narrowest
+func GetConfidenceLevels(config *model.Config) []ConfidenceLevel {
+	levels := make([]ConfidenceLevel, 0, len(DefaultConfidenceLevels)+len(config.ConfidenceLevels))
+	seen := make(map[string]bool)
+
+	for _, level := range DefaultConfidenceLevels {
+		levels = append(levels, ResolveConfidenceLevel(config, level))
+		seen[level.Name] = true
+	}
+
+	for name, multiplier := range config.ConfidenceLevels {
+		if seen[name] || multiplier <= 0 {
+			continue
+		}
+		levels = append(levels, ConfidenceLevel{Name: name, Multiplier: multiplier})
+	}
+
+	sort.SliceStable(levels, func(i, j int) bool {
+		if levels[i].Multiplier == levels[j].Multiplier {
+			return levels[i].Name < levels[j].Name
+		}
+		return levels[i].Multiplier > levels[j].Multiplier
+	})
+
+	return levels
+}
+
+// ConfidenceForLevel returns the two-sided confidence level of the normal
+// distribution for the given percentage (e.g. 85 for 85%). The percentage is
+// clamped to the open interval ]0, 100[.
+func ConfidenceForLevel(percent float64) ConfidenceLevel {
+	percent = math.Max(math.SmallestNonzeroFloat64, math.Min(percent, 99.99))
+	return ConfidenceLevel{
+		Name:       strconv.FormatFloat(percent, 'f', -1, 64) + "%",
+		Multiplier: math.Sqrt2 * math.Erfinv(percent/100),
+	}
+}
+
+// ParseConfidenceLevel returns the confidence level matching the given value, either
+// the name of a configured level (e.g. "90%" or "90") or any percentage in ]0, 100[
+func ParseConfidenceLevel(config *model.Config, value string) (ConfidenceLevel, error) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+
+	for _, level := range GetConfidenceLevels(config) {
+		if level.Name == value+"%" {
+			return level, nil
+		}
+	}
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent <= 0 || percent >= 100 {
+		return ConfidenceLevel{}, fmt.Errorf("invalid confidence level '%s', expected a percentage between 0 and 100", value)
+	}
+
+	return ConfidenceForLevel(percent), nil
+}
+
+// CostConfidenceLevel returns the confidence level used to compute the cost
+// estimation, as configured by costConfidence (default: 99.7%)
+func CostConfidenceLevel(config *model.Config) ConfidenceLevel {
+	if config.CostConfidence != "" {
+		if level, err := ParseConfidenceLevel(config, config.CostConfidence); err == nil {
+			return level
+		}
+	}
+	return ResolveConfidenceLevel(config, Confidence997)
+}
+
 // CalculateEstimation calculates the weighted mean and standard deviation for a task
 func CalculateEstimation(task *model.Task) EstimationResult {
 	return EstimationResult{
@@ -33,14 +156,111 @@ func CalculateEstimation(task *model.Task) EstimationResult {
 	}
 }
 
-// CalculateProjectEstimation calculates the weighted mean and standard deviation for an entire project
+// CalculateProjectEstimation calculates the weighted mean and standard deviation for an entire project,
+// assuming independent tasks
 func CalculateProjectEstimation(estimation *model.Estimation) EstimationResult {
+	return CalculateProjectEstimationWithCorrelation(estimation, 0)
+}
+
+// CalculateProjectEstimationWithCorrelation calculates the weighted mean and standard deviation for an
+// entire project, assuming every pair of tasks shares the same correlation coefficient ρ in [0, 1].
+//
+// The total variance interpolates between independent and fully correlated tasks:
+//
+//	Var = (1 - ρ) * Σ SDᵢ² + ρ * (Σ SDᵢ)²
+//
+// which is the exact variance of the sum when Cov(i, j) = ρ * SDᵢ * SDⱼ for i ≠ j.
+// ρ = 0 gives the sum of variances, ρ = 1 gives the square of the sum of standard deviations.
+func CalculateProjectEstimationWithCorrelation(estimation *model.Estimation, correlation float64) EstimationResult {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	return calculateProjectEstimation(tasks, correlation, model.EstimationModelPERT6Sigma, nil)
+}
+
+// CalculateProjectEstimationFor calculates the weighted mean and standard deviation for an
+// entire project, using the correlation coefficient, estimation model and task confidence
+// factors of the configuration
+func CalculateProjectEstimationFor(estimation *model.Estimation, config *model.Config) EstimationResult {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	return calculateProjectEstimationFor(tasks, config)
+}
+
+// calculateProjectEstimationFor is CalculateProjectEstimationFor over already sorted tasks
+func calculateProjectEstimationFor(tasks []*model.Task, config *model.Config) EstimationResult {
+	return calculateProjectEstimation(tasks, config.GetCorrelationCoefficient(), config.GetEstimationModel(), config.GetTaskConfidenceFactor)
+}
+
+// calculateProjectEstimation inflates the standard deviation of each task by the factor of
+// its confidence level when confidenceFactor is not nil
+func calculateProjectEstimation(tasks []*model.Task, correlation float64, estimationModel string, confidenceFactor func(level string) float64) EstimationResult {
 	var totalMean float64
 	var totalVariance float64
+	var totalDeviation float64
 
-	for _, task := range estimation.Tasks {
+	for _, task := range tasks {
+		sd := task.StandardDeviationWith(estimationModel)
+		if confidenceFactor != nil {
+			sd *= confidenceFactor(task.Confidence)
+		}
 		totalMean += task.WeightedMean()
-		totalVariance += math.Pow(task.StandardDeviation(), 2)
+		totalVariance += math.Pow(sd, 2)
+		totalDeviation += sd
+	}
+
+	correlation = math.Max(0, math.Min(1, correlation))
+	totalVariance = (1-correlation)*totalVariance + correlation*math.Pow(totalDeviation, 2)
+
+	return EstimationResult{
+		WeightedMean:      totalMean,
+		StandardDeviation: math.Sqrt(totalVariance),
+	}
+}
+
+// taskBuffers recycles the slices of sortedTasks across calculations
+var taskBuffers = sync.Pool{
+	New: func() any { return new([]*model.Task) },
+}
+
+// sortedTasks returns the tasks of an estimation sorted by ID, so that floating point sums
+// over them don't depend on the map iteration order and are reproducible. The tasks are
+// collected in a recycled buffer, to be given back with release once the calculation is
+// done: a calculation sorts them once and passes them down to the ones it depends on.
+func sortedTasks(estimation *model.Estimation) ([]*model.Task, func()) {
+	buf := taskBuffers.Get().(*[]*model.Task)
+
+	tasks := (*buf)[:0]
+	for _, task := range estimation.Tasks {
+		tasks = append(tasks, task)
+	}
+	slices.SortFunc(tasks, func(a, b *model.Task) int { return strings.Compare(string(a.ID), string(b.ID)) })
+
+	return tasks, func() {
+		// Don't keep the tasks alive through the pool
+		clear(tasks)
+		*buf = tasks[:0]
+		taskBuffers.Put(buf)
+	}
+}
+
+// CalendarWeeks converts a value expressed in the configured time unit into calendar weeks,
+// given the configured working days per week and the number of people sharing the work
+// (a team size <= 0 being a single person)
+func CalendarWeeks(config *model.Config, value float64, teamSize int) float64 {
+	return config.ToWorkingDays(value) / float64(max(1, teamSize)) / config.GetWorkingDaysPerWeek()
+}
+
+// CombineEstimations combines independent estimation results by summing their
+// weighted means and variances
+func CombineEstimations(results ...EstimationResult) EstimationResult {
+	var totalMean float64
+	var totalVariance float64
+
+	for _, result := range results {
+		totalMean += result.WeightedMean
+		totalVariance += math.Pow(result.StandardDeviation, 2)
 	}
 
 	return EstimationResult{
@@ -54,7 +274,10 @@ func CalculateCategoryEstimation(estimation *model.Estimation, categoryID string
 	var totalMean float64
 	var totalVariance float64
 
-	for _, task := range estimation.Tasks {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	for _, task := range tasks {
 		if task.Category == categoryID {
 			totalMean += task.WeightedMean()
 			totalVariance += math.Pow(task.StandardDeviation(), 2)
@@ -73,62 +296,141 @@ type CategoryDistribution struct {
 	CategoryLabel string
 	Time          float64
 	Percentage    float64
+	// StandardDeviation of the category own tasks, used by the independent cost model
+	StandardDeviation float64
+}
+
+// Scale returns the distribution with its time and standard deviation multiplied by
+// factor, e.g. to convert story points into time
+func (d CategoryDistribution) Scale(factor float64) CategoryDistribution {
+	d.Time *= factor
+	d.StandardDeviation *= factor
+	return d
 }
 
 // CalculateCategoryDistribution calculates the distribution of time across categories
+// in a single pass over the tasks
 func CalculateCategoryDistribution(estimation *model.Estimation, config *model.Config) []CategoryDistribution {
-	projectEst := CalculateProjectEstimation(estimation)
-	if projectEst.WeightedMean == 0 {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	return calculateCategoryDistribution(tasks, config)
+}
+
+// calculateCategoryDistribution is CalculateCategoryDistribution over already sorted tasks
+func calculateCategoryDistribution(tasks []*model.Task, config *model.Config) []CategoryDistribution {
+	var totalMean float64
+	categoryMeans := make(map[string]float64)
+	categoryVariances := make(map[string]float64)
+	categoryDeviations := make(map[string]float64)
+	estimationModel := config.GetEstimationModel()
+
+	// Tasks categories that are not in the config, sorted by ID once collected
+	var unknownCategories []string
+
+	for _, task := range tasks {
+		mean := task.WeightedMean()
+		totalMean += mean
+
+		if _, ok := categoryMeans[task.Category]; !ok {
+			if _, configured := config.TaskCategories[task.Category]; !configured {
+				unknownCategories = append(unknownCategories, task.Category)
+			}
+		}
+		categoryMeans[task.Category] += mean
+
+		sd := task.StandardDeviationWith(estimationModel) * config.GetTaskConfidenceFactor(task.Confidence)
+		categoryVariances[task.Category] += math.Pow(sd, 2)
+		categoryDeviations[task.Category] += sd
+	}
+
+	if totalMean == 0 {
 		return nil
 	}
+	slices.Sort(unknownCategories)
+
+	distributions := make([]CategoryDistribution, 0, len(config.TaskCategories)+len(unknownCategories))
 
-	distributions := make([]CategoryDistribution, 0)
-	seenCategories := make(map[string]bool)
+	// The tasks of a category are correlated as the ones of the project
+	correlation := math.Max(0, math.Min(1, config.GetCorrelationCoefficient()))
 
-	// First, process configured categories
-	for catID, cat := range config.TaskCategories {
-		catEst := CalculateCategoryEstimation(estimation, catID)
+	newDistribution := func(catID string, label string) CategoryDistribution {
 		percentage := 0.0
-		if projectEst.WeightedMean > 0 {
-			percentage = (catEst.WeightedMean / projectEst.WeightedMean) * 100
+		if totalMean > 0 {
+			percentage = (categoryMeans[catID] / totalMean) * 100
+		}
+		variance := (1-correlation)*categoryVariances[catID] + correlation*math.Pow(categoryDeviations[catID], 2)
+		return CategoryDistribution{
+			CategoryID:        catID,
+			CategoryLabel:     label,
+			Time:              categoryMeans[catID],
+			Percentage:        percentage,
+			StandardDeviation: math.Sqrt(variance),
 		}
+	}
 
-		distributions = append(distributions, CategoryDistribution{
-			CategoryID:    catID,
-			CategoryLabel: cat.Label,
-			Time:          catEst.WeightedMean,
-			Percentage:    percentage,
-		})
-		seenCategories[catID] = true
+	// First, process configured categories, sorted by ID
+	for _, catID := range slices.Sorted(maps.Keys(config.TaskCategories)) {
+		distributions = append(distributions, newDistribution(catID, config.TaskCategories[catID].Label))
 	}
 
 	// Then, add any categories from tasks that are not in the config
-	for _, task := range estimation.Tasks {
-		if !seenCategories[task.Category] {
-			catEst := CalculateCategoryEstimation(estimation, task.Category)
-			percentage := 0.0
-			if projectEst.WeightedMean > 0 {
-				percentage = (catEst.WeightedMean / projectEst.WeightedMean) * 100
-			}
-			cat := config.GetTaskCategory(task.Category)
-			distributions = append(distributions, CategoryDistribution{
-				CategoryID:    task.Category,
-				CategoryLabel: cat.Label,
-				Time:          catEst.WeightedMean,
-				Percentage:    percentage,
-			})
-			seenCategories[task.Category] = true
-		}
+	for _, catID := range unknownCategories {
+		distributions = append(distributions, newDistribution(catID, config.GetTaskCategory(catID).Label))
 	}
 
 	return distributions
 }
 
+// SortByShare returns a copy of the distribution sorted by descending percentage, so that
+// the dominant category comes first. Ties are broken by label, then by ID.
+func SortByShare(distribution []CategoryDistribution) []CategoryDistribution {
+	sorted := slices.Clone(distribution)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Percentage != sorted[j].Percentage {
+			return sorted[i].Percentage > sorted[j].Percentage
+		}
+		if sorted[i].CategoryLabel != sorted[j].CategoryLabel {
+			return sorted[i].CategoryLabel < sorted[j].CategoryLabel
+		}
+		return sorted[i].CategoryID < sorted[j].CategoryID
+	})
+	return sorted
+}
+
+// DistributionTolerance is the relative tolerance of CheckCategoryDistribution
+const DistributionTolerance = 1e-6
+
+// CheckCategoryDistribution cross-checks the category distribution against the project
+// estimation, returning the inconsistencies found: category times not summing to the project
+// weighted mean, or percentages not summing to 100%, within DistributionTolerance
+func CheckCategoryDistribution(projectEst EstimationResult, distribution []CategoryDistribution) []string {
+	var issues []string
+
+	var totalTime, totalPercentage float64
+	for _, dist := range distribution {
+		totalTime += dist.Time
+		totalPercentage += dist.Percentage
+	}
+
+	if math.Abs(totalTime-projectEst.WeightedMean) > DistributionTolerance*math.Max(1, math.Abs(projectEst.WeightedMean)) {
+		issues = append(issues, fmt.Sprintf("category times sum to %g instead of the project mean %g", totalTime, projectEst.WeightedMean))
+	}
+	if len(distribution) > 0 && math.Abs(totalPercentage-100) > DistributionTolerance*100 {
+		issues = append(issues, fmt.Sprintf("category percentages sum to %g%% instead of 100%%", totalPercentage))
+	}
+
+	return issues
+}
+
 // CostEstimation represents cost estimation results
 type CostEstimation struct {
 	TotalTime float64
 	TotalCost float64
 	Details   map[string]CategoryCost
+
+	// FixedCost is the part of TotalCost coming from the task fixed costs
+	FixedCost float64
 }
 
 // CategoryCost represents cost details for a category
@@ -144,28 +446,85 @@ type MinMaxCost struct {
 	Max CostEstimation
 }
 
+// AdjustedCost is a cost with the markup then the discount of the configuration applied.
+// Every amount is rounded to the cent, so that Subtotal + Markup - Discount = Total.
+type AdjustedCost struct {
+	Subtotal float64
+	Markup   float64
+	Discount float64
+	Total    float64
+}
+
+// AdjustCost applies the markup of the configuration to the cost, then its discount to
+// the marked-up cost, both being percentages
+func AdjustCost(cost float64, config *model.Config) AdjustedCost {
+	subtotal := roundCents(cost)
+	markup := roundCents(subtotal * config.Markup / 100)
+	discount := roundCents((subtotal + markup) * config.Discount / 100)
+
+	return AdjustedCost{
+		Subtotal: subtotal,
+		Markup:   markup,
+		Discount: discount,
+		Total:    roundCents(subtotal + markup - discount),
+	}
+}
+
+// roundCents rounds an amount to the nearest cent
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
 // CalculateMinMaxCosts calculates the min and max cost estimates for a given confidence level
 func CalculateMinMaxCosts(estimation *model.Estimation, config *model.Config, confidence ConfidenceLevel) MinMaxCost {
-	projectEst := CalculateProjectEstimation(estimation)
-	distribution := CalculateCategoryDistribution(estimation, config)
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	projectEst := calculateProjectEstimationFor(tasks, config)
+	distribution := calculateCategoryDistribution(tasks, config)
 
+	return CalculateMinMaxCostsFrom(projectEst, distribution, calculateFixedCost(tasks), config, confidence)
+}
+
+// CalculateMinMaxCostsFrom calculates the min and max cost estimates for a given confidence level
+// from an already computed project estimation, category distribution and fixed cost, sparing
+// callers that need them too from iterating over the tasks again. Being certain, the fixed cost
+// is added to both the min and max costs.
+//
+// With the proportional cost model, the project min and max times are spread over the
+// categories by their share of the weighted mean. With the independent one, the min and
+// max times of each category come from its own weighted mean and standard deviation, so
+// that a small but uncertain category keeps its own variance: as the bounds of the
+// categories are summed, the project range is generally wider.
+func CalculateMinMaxCostsFrom(projectEst EstimationResult, distribution []CategoryDistribution, fixedCost float64, config *model.Config, confidence ConfidenceLevel) MinMaxCost {
 	minCost := CostEstimation{
-		Details: make(map[string]CategoryCost),
+		TotalCost: fixedCost,
+		Details:   make(map[string]CategoryCost),
+		FixedCost: fixedCost,
 	}
 	maxCost := CostEstimation{
-		Details: make(map[string]CategoryCost),
+		TotalCost: fixedCost,
+		Details:   make(map[string]CategoryCost),
+		FixedCost: fixedCost,
 	}
 
 	// Calculate min estimate (E - SD * multiplier)
 	minTime := math.Max(0, projectEst.WeightedMean-projectEst.StandardDeviation*confidence.Multiplier)
 	// Calculate max estimate (E + SD * multiplier)
 	maxTime := projectEst.WeightedMean + projectEst.StandardDeviation*confidence.Multiplier
+	independent := config.GetCostModel() == model.CostModelIndependent
 
 	for _, dist := range distribution {
 		cat := config.GetTaskCategory(dist.CategoryID)
 
-		// Min time for this category
+		// Min and max times for this category
 		minCatTime := (dist.Percentage / 100) * minTime
+		maxCatTime := (dist.Percentage / 100) * maxTime
+		if independent {
+			minCatTime = math.Max(0, dist.Time-dist.StandardDeviation*confidence.Multiplier)
+			maxCatTime = dist.Time + dist.StandardDeviation*confidence.Multiplier
+		}
+
 		minCatCost := minCatTime * cat.CostPerTimeUnit
 		minCost.Details[dist.CategoryID] = CategoryCost{
 			Time:        minCatTime,
@@ -175,8 +534,6 @@ func CalculateMinMaxCosts(estimation *model.Estimation, config *model.Config, co
 		minCost.TotalTime += minCatTime
 		minCost.TotalCost += minCatCost
 
-		// Max time for this category
-		maxCatTime := (dist.Percentage / 100) * maxTime
 		maxCatCost := maxCatTime * cat.CostPerTimeUnit
 		maxCost.Details[dist.CategoryID] = CategoryCost{
 			Time:        maxCatTime,
@@ -193,6 +550,278 @@ func CalculateMinMaxCosts(estimation *model.Estimation, config *model.Config, co
 	}
 }
 
+// CalculateTaskMinMaxCost distributes the min and max costs of the task category down to
+// the task, proportionally to its share of the category weighted mean, so that the costs of
+// the tasks of a category sum to the category ones
+func CalculateTaskMinMaxCost(task *model.Task, distribution []CategoryDistribution, costs MinMaxCost) (CategoryCost, CategoryCost) {
+	var share float64
+	for _, dist := range distribution {
+		if dist.CategoryID == task.Category && dist.Time > 0 {
+			share = task.WeightedMean() / dist.Time
+			break
+		}
+	}
+
+	scale := func(catCost CategoryCost) CategoryCost {
+		return CategoryCost{
+			Time:        catCost.Time * share,
+			Cost:        catCost.Cost * share,
+			CostPerUnit: catCost.CostPerUnit,
+		}
+	}
+
+	return scale(costs.Min.Details[task.Category]), scale(costs.Max.Details[task.Category])
+}
+
+// CalculateFixedCost sums the fixed costs of the tasks of an estimation
+func CalculateFixedCost(estimation *model.Estimation) float64 {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	return calculateFixedCost(tasks)
+}
+
+// calculateFixedCost is CalculateFixedCost over already sorted tasks
+func calculateFixedCost(tasks []*model.Task) float64 {
+	var fixedCost float64
+	for _, task := range tasks {
+		fixedCost += task.FixedCost
+	}
+	return fixedCost
+}
+
+// CalculateExpectedCost calculates the cost of an estimation at its weighted mean, fixed
+// costs included
+func CalculateExpectedCost(estimation *model.Estimation, config *model.Config) float64 {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	totalCost := calculateFixedCost(tasks)
+
+	for _, dist := range calculateCategoryDistribution(tasks, config) {
+		cat := config.GetTaskCategory(dist.CategoryID)
+		totalCost += dist.Time * cat.CostPerTimeUnit
+	}
+
+	return totalCost
+}
+
+// CalculateTaskCost calculates the cost of a task at its weighted mean, its fixed cost included
+func CalculateTaskCost(task *model.Task, config *model.Config) float64 {
+	cat := config.GetTaskCategory(task.Category)
+	return task.WeightedMean()*cat.CostPerTimeUnit + task.FixedCost
+}
+
+// UnassignedLabel is the label used to group tasks without assignee
+const UnassignedLabel = "(unassigned)"
+
+// AssigneeWorkload represents the workload of a single assignee
+type AssigneeWorkload struct {
+	Assignee          string
+	Tasks             int
+	WeightedMean      float64
+	StandardDeviation float64
+	Cost              float64
+	Percentage        float64
+}
+
+// CalculateWorkload groups the tasks of an estimation by assignee, sorted by
+// assignee name with unassigned tasks last
+func CalculateWorkload(estimation *model.Estimation, config *model.Config) []AssigneeWorkload {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	projectEst := calculateProjectEstimation(tasks, 0, model.EstimationModelPERT6Sigma, nil)
+
+	variances := make(map[string]float64)
+	workloads := make(map[string]*AssigneeWorkload)
+	for _, task := range tasks {
+		assignee := task.Assignee
+		if assignee == "" {
+			assignee = UnassignedLabel
+		}
+
+		workload, ok := workloads[assignee]
+		if !ok {
+			workload = &AssigneeWorkload{Assignee: assignee}
+			workloads[assignee] = workload
+		}
+
+		workload.Tasks++
+		workload.WeightedMean += task.WeightedMean()
+		workload.Cost += CalculateTaskCost(task, config)
+		sd := task.StandardDeviationWith(config.GetEstimationModel()) * config.GetTaskConfidenceFactor(task.Confidence)
+		variances[assignee] += math.Pow(sd, 2)
+	}
+
+	result := make([]AssigneeWorkload, 0, len(workloads))
+	for assignee, workload := range workloads {
+		workload.StandardDeviation = math.Sqrt(variances[assignee])
+		if projectEst.WeightedMean > 0 {
+			workload.Percentage = (workload.WeightedMean / projectEst.WeightedMean) * 100
+		}
+		result = append(result, *workload)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Assignee == UnassignedLabel || result[j].Assignee == UnassignedLabel {
+			return result[j].Assignee == UnassignedLabel && result[i].Assignee != UnassignedLabel
+		}
+		return result[i].Assignee < result[j].Assignee
+	})
+
+	return result
+}
+
+// UntaggedLabel is the label used to group tasks without tags
+const UntaggedLabel = "(untagged)"
+
+// TagSubtotal represents the tasks sharing a tag. Tasks may have several tags, so that
+// the subtotals of the tags overlap and don't sum to the project total.
+type TagSubtotal struct {
+	Tag          string
+	Tasks        int
+	WeightedMean float64
+	Cost         float64 // Cost of the effort at the weighted mean
+	FixedCost    float64
+	Percentage   float64 // Share of the project weighted mean
+}
+
+// Scale returns the subtotal with its weighted mean and effort cost multiplied by factor,
+// e.g. to convert story points into time
+func (t TagSubtotal) Scale(factor float64) TagSubtotal {
+	t.WeightedMean *= factor
+	t.Cost *= factor
+	return t
+}
+
+// CalculateTagSubtotals groups the tasks of an estimation by tag, a task being counted in
+// each of its tags, sorted by tag with untagged tasks last
+func CalculateTagSubtotals(estimation *model.Estimation, config *model.Config) []TagSubtotal {
+	tasks, release := sortedTasks(estimation)
+	defer release()
+
+	projectEst := calculateProjectEstimation(tasks, 0, model.EstimationModelPERT6Sigma, nil)
+
+	subtotals := make(map[string]*TagSubtotal)
+	add := func(tag string, task *model.Task) {
+		subtotal, ok := subtotals[tag]
+		if !ok {
+			subtotal = &TagSubtotal{Tag: tag}
+			subtotals[tag] = subtotal
+		}
+
+		subtotal.Tasks++
+		subtotal.WeightedMean += task.WeightedMean()
+		subtotal.Cost += task.WeightedMean() * config.GetTaskCategory(task.Category).CostPerTimeUnit
+		subtotal.FixedCost += task.FixedCost
+	}
+
+	for _, task := range tasks {
+		if len(task.Tags) == 0 {
+			add(UntaggedLabel, task)
+			continue
+		}
+		for _, tag := range slices.Compact(slices.Sorted(slices.Values(task.Tags))) {
+			add(tag, task)
+		}
+	}
+
+	result := make([]TagSubtotal, 0, len(subtotals))
+	for _, subtotal := range subtotals {
+		if projectEst.WeightedMean > 0 {
+			subtotal.Percentage = (subtotal.WeightedMean / projectEst.WeightedMean) * 100
+		}
+		result = append(result, *subtotal)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Tag == UntaggedLabel || result[j].Tag == UntaggedLabel {
+			return result[j].Tag == UntaggedLabel && result[i].Tag != UntaggedLabel
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return result
+}
+
+// Named scenarios of ApplyScenario
+const (
+	ScenarioOptimistic  = "optimistic"
+	ScenarioExpected    = "expected"
+	ScenarioPessimistic = "pessimistic"
+	// ScenarioStress is followed by the factor scaling every estimate, e.g. "stress×1.5"
+	// (or "stressx1.5"), defaulting to DefaultStressFactor
+	ScenarioStress = "stress"
+)
+
+// DefaultStressFactor is the factor of the stress scenario given without one
+const DefaultStressFactor = 1.5
+
+// Scenarios returns the names of the scenarios, the stress one with its default factor
+func Scenarios() []string {
+	return []string{ScenarioOptimistic, ScenarioExpected, ScenarioPessimistic, fmt.Sprintf("%s×%g", ScenarioStress, DefaultStressFactor)}
+}
+
+// ApplyScenario returns a copy of the estimation with the estimates of every task shifted
+// by the named scenario, the estimation itself being left untouched:
+//   - optimistic: the likely estimate becomes the optimistic one
+//   - expected: the estimates are unchanged
+//   - pessimistic: the likely estimate becomes the pessimistic one
+//   - stress×F: the three estimates are scaled by F
+func ApplyScenario(estimation *model.Estimation, scenario string) (*model.Estimation, error) {
+	var transform func(e *model.Estimations)
+
+	switch {
+	case scenario == ScenarioOptimistic:
+		transform = func(e *model.Estimations) { e.Likely = e.Optimistic }
+	case scenario == ScenarioExpected:
+		transform = func(e *model.Estimations) {}
+	case scenario == ScenarioPessimistic:
+		transform = func(e *model.Estimations) { e.Likely = e.Pessimistic }
+	case strings.HasPrefix(scenario, ScenarioStress):
+		factor, err := parseStressFactor(strings.TrimPrefix(scenario, ScenarioStress))
+		if err != nil {
+			return nil, fmt.Errorf("invalid scenario '%s': %w", scenario, err)
+		}
+		transform = func(e *model.Estimations) {
+			e.Optimistic *= factor
+			e.Likely *= factor
+			e.Pessimistic *= factor
+		}
+	default:
+		return nil, fmt.Errorf("unknown scenario '%s', expected one of: %s", scenario, strings.Join(Scenarios(), ", "))
+	}
+
+	shifted := estimation.Clone()
+	for _, task := range shifted.Tasks {
+		transform(&task.Estimations)
+	}
+
+	return shifted, nil
+}
+
+// parseStressFactor parses the "×F" or "xF" suffix of the stress scenario
+func parseStressFactor(suffix string) (float64, error) {
+	if suffix == "" {
+		return DefaultStressFactor, nil
+	}
+
+	value, ok := strings.CutPrefix(suffix, "×")
+	if !ok {
+		if value, ok = strings.CutPrefix(suffix, "x"); !ok {
+			return 0, fmt.Errorf("expected stress×<factor>")
+		}
+	}
+
+	factor, err := strconv.ParseFloat(value, 64)
+	if err != nil || factor <= 0 {
+		return 0, fmt.Errorf("the stress factor must be a number > 0")
+	}
+
+	return factor, nil
+}
+
 // FormatEstimation formats an estimation value with optional rounding
 func FormatEstimation(value float64, roundUp bool) float64 {
 	if roundUp {
@@ -0,0 +1,262 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+// newLargeEstimation returns an estimation of n tasks spread over the default categories
+// and an unknown one
+func newLargeEstimation(n int) *model.Estimation {
+	categories := []string{"development", "testing", "project-management", "design"}
+
+	estimation := model.NewEstimation("large")
+	for i := range n {
+		task := model.NewTask(fmt.Sprintf("Task %d", i), categories[i%len(categories)])
+		task.ID = model.TaskID(fmt.Sprintf("t%d", i))
+		task.Estimations = model.Estimations{
+			Optimistic:  float64(1 + i%3),
+			Likely:      float64(2 + i%5),
+			Pessimistic: float64(8 + i%7),
+		}
+		estimation.AddTask(task)
+	}
+
+	return estimation
+}
+
+// BenchmarkCalculateProjectEstimation compares computing the summary figures the way it
+// was done before, each function iterating over the tasks again, with computing the
+// project estimation and the distribution once and deriving the costs from them
+func BenchmarkCalculateProjectEstimation(b *testing.B) {
+	config := model.DefaultConfig()
+	confidence := CostConfidenceLevel(config)
+
+	for _, n := range []int{10, 1000, 10000} {
+		estimation := newLargeEstimation(n)
+
+		b.Run(fmt.Sprintf("recompute/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				CalculateProjectEstimationFor(estimation, config)
+				CalculateCategoryDistribution(estimation, config)
+				CalculateMinMaxCosts(estimation, config, confidence)
+			}
+		})
+
+		b.Run(fmt.Sprintf("reuse/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				projectEst := CalculateProjectEstimationFor(estimation, config)
+				distribution := CalculateCategoryDistribution(estimation, config)
+				CalculateMinMaxCostsFrom(projectEst, distribution, CalculateFixedCost(estimation), config, confidence)
+			}
+		})
+	}
+}
+
+func TestCalculateMinMaxCostsFromMatchesCalculateMinMaxCosts(t *testing.T) {
+	config := model.DefaultConfig()
+	confidence := CostConfidenceLevel(config)
+	estimation := newLargeEstimation(100)
+
+	projectEst := CalculateProjectEstimationFor(estimation, config)
+	distribution := CalculateCategoryDistribution(estimation, config)
+
+	got := CalculateMinMaxCostsFrom(projectEst, distribution, CalculateFixedCost(estimation), config, confidence)
+	want := CalculateMinMaxCosts(estimation, config, confidence)
+
+	// The categories being summed in map order, the totals only match up to rounding
+	if !almostEqual(got.Min.TotalCost, want.Min.TotalCost) || !almostEqual(got.Max.TotalCost, want.Max.TotalCost) {
+		t.Errorf("costs = %v/%v, want %v/%v", got.Min.TotalCost, got.Max.TotalCost, want.Min.TotalCost, want.Max.TotalCost)
+	}
+	if !almostEqual(got.Min.TotalTime, want.Min.TotalTime) || !almostEqual(got.Max.TotalTime, want.Max.TotalTime) {
+		t.Errorf("times = %v/%v, want %v/%v", got.Min.TotalTime, got.Max.TotalTime, want.Min.TotalTime, want.Max.TotalTime)
+	}
+}
+
+// almostEqual reports whether two values only differ by floating point rounding
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestCalculateCategoryDistributionSortsUnknownCategories(t *testing.T) {
+	config := model.DefaultConfig()
+
+	estimation := model.NewEstimation("unknown")
+	for _, category := range []string{"zeta", "alpha", "mu", "alpha"} {
+		task := model.NewTask(category, category)
+		task.Estimations = model.Estimations{Optimistic: 1, Likely: 2, Pessimistic: 3}
+		estimation.AddTask(task)
+	}
+
+	for range 10 {
+		distribution := CalculateCategoryDistribution(estimation, config)
+
+		var unknown []string
+		for _, dist := range distribution {
+			if _, ok := config.TaskCategories[dist.CategoryID]; !ok {
+				unknown = append(unknown, dist.CategoryID)
+			}
+		}
+
+		if fmt.Sprint(unknown) != "[alpha mu zeta]" {
+			t.Fatalf("unknown categories = %v, want [alpha mu zeta]", unknown)
+		}
+	}
+}
+
+func TestEmptyEstimations(t *testing.T) {
+	zeroTask := func(category string) *model.Task {
+		return model.NewTask("Not estimated yet", category)
+	}
+
+	tests := []struct {
+		name  string
+		tasks []*model.Task
+	}{
+		{name: "no tasks"},
+		{name: "all-zero estimates", tasks: []*model.Task{zeroTask("development"), zeroTask("testing"), zeroTask("unknown")}},
+	}
+
+	config := model.DefaultConfig()
+	confidence := CostConfidenceLevel(config)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimation := model.NewEstimation(tt.name)
+			for _, task := range tt.tasks {
+				estimation.AddTask(task)
+			}
+
+			projectEst := CalculateProjectEstimationFor(estimation, config)
+			if !projectEst.IsEmpty() {
+				t.Errorf("project estimation = %+v, want empty", projectEst)
+			}
+			if p := projectEst.Probability(0); math.IsNaN(p) {
+				t.Errorf("probability = NaN")
+			}
+
+			if distribution := CalculateCategoryDistribution(estimation, config); distribution != nil {
+				t.Errorf("distribution = %+v, want nil", distribution)
+			}
+
+			costs := CalculateMinMaxCosts(estimation, config, confidence)
+			for name, value := range map[string]float64{
+				"min time": costs.Min.TotalTime,
+				"min cost": costs.Min.TotalCost,
+				"max time": costs.Max.TotalTime,
+				"max cost": costs.Max.TotalCost,
+			} {
+				if value != 0 {
+					t.Errorf("%s = %v, want 0", name, value)
+				}
+			}
+			if len(costs.Min.Details) != 0 || len(costs.Max.Details) != 0 {
+				t.Errorf("cost details = %v/%v, want none", costs.Min.Details, costs.Max.Details)
+			}
+
+			if cost := CalculateExpectedCost(estimation, config); cost != 0 {
+				t.Errorf("expected cost = %v, want 0", cost)
+			}
+
+			for _, workload := range CalculateWorkload(estimation, config) {
+				if workload.WeightedMean != 0 || math.IsNaN(workload.Percentage) || math.IsNaN(workload.StandardDeviation) {
+					t.Errorf("workload = %+v, want zero values", workload)
+				}
+			}
+
+			for _, subtotal := range CalculateTagSubtotals(estimation, config) {
+				if subtotal.WeightedMean != 0 || math.IsNaN(subtotal.Percentage) {
+					t.Errorf("tag subtotal = %+v, want zero values", subtotal)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryDistributionReconciles(t *testing.T) {
+	capped := model.NewTask("Spike", "development")
+	capped.Estimations = model.Estimations{Optimistic: 2, Likely: 4, Pessimistic: 8}
+	maxEstimate := 3.0
+	capped.MaxEstimate = &maxEstimate
+
+	uncertain := model.NewTask("Integration", "unknown")
+	uncertain.Estimations = model.Estimations{Optimistic: 0.1, Likely: 0.7, Pessimistic: 3.3}
+	uncertain.Confidence = "low"
+
+	tests := []struct {
+		name       string
+		estimation *model.Estimation
+	}{
+		{name: "configured categories", estimation: newLargeEstimation(3)},
+		{name: "unknown categories", estimation: newLargeEstimation(1000)},
+		{name: "capped and uncertain tasks", estimation: func() *model.Estimation {
+			estimation := newLargeEstimation(7)
+			estimation.AddTask(capped)
+			estimation.AddTask(uncertain)
+			return estimation
+		}()},
+		{name: "no tasks", estimation: model.NewEstimation("empty")},
+	}
+
+	config := model.DefaultConfig()
+	config.CorrelationCoefficient = 0.3
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectEst := CalculateProjectEstimationFor(tt.estimation, config)
+			distribution := CalculateCategoryDistribution(tt.estimation, config)
+
+			if issues := CheckCategoryDistribution(projectEst, distribution); len(issues) > 0 {
+				t.Errorf("distribution doesn't reconcile: %v", issues)
+			}
+		})
+	}
+}
+
+func TestCheckCategoryDistributionFlagsDiscrepancies(t *testing.T) {
+	config := model.DefaultConfig()
+	estimation := newLargeEstimation(10)
+
+	projectEst := CalculateProjectEstimationFor(estimation, config)
+	distribution := CalculateCategoryDistribution(estimation, config)
+
+	// A category left out, as when unknown categories were ignored
+	if issues := CheckCategoryDistribution(projectEst, distribution[1:]); len(issues) != 2 {
+		t.Errorf("issues = %v, want the time and percentage discrepancies", issues)
+	}
+
+	// A drift beyond the tolerance
+	drifted := slices.Clone(distribution)
+	drifted[0].Time += projectEst.WeightedMean * 1e-3
+	if issues := CheckCategoryDistribution(projectEst, drifted); len(issues) != 1 {
+		t.Errorf("issues = %v, want the time discrepancy", issues)
+	}
+}
+
+func TestCalculationsAreReproducible(t *testing.T) {
+	estimation := newLargeEstimation(1000)
+	config := model.DefaultConfig()
+
+	want := CalculateMinMaxCosts(estimation, config, CostConfidenceLevel(config))
+	for range 20 {
+		if got := CalculateMinMaxCosts(estimation, config, CostConfidenceLevel(config)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("CalculateMinMaxCosts() = %+v, then %+v", want, got)
+		}
+	}
+}
+
+func TestSortedTasksAreRecycled(t *testing.T) {
+	estimation := newLargeEstimation(1000)
+
+	// Only the release function is allocated once the buffer is in the pool
+	if allocs := testing.AllocsPerRun(100, func() { CalculateFixedCost(estimation) }); allocs > 1 {
+		t.Errorf("CalculateFixedCost() allocations = %v, want at most 1", allocs)
+	}
+}
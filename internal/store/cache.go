@@ -0,0 +1,118 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+// configCacheEntry is a parsed configuration along with the state of its file when parsed
+type configCacheEntry struct {
+	ModTime time.Time
+	Size    int64
+	Config  *model.Config
+}
+
+// isFresh reports whether the entry was parsed from the file in its given state
+func (e configCacheEntry) isFresh(info os.FileInfo) bool {
+	return e.ModTime.Equal(info.ModTime()) && e.Size == info.Size()
+}
+
+// configCache holds the parsed configurations of the process, keyed by absolute file path,
+// and the config file paths resolved by walking up from a working directory
+var configCache = struct {
+	sync.Mutex
+	entries map[string]configCacheEntry
+	paths   map[string]string
+}{entries: make(map[string]configCacheEntry), paths: make(map[string]string)}
+
+// WithConfigCache enables the cache of parsed configuration files on the store. A cached
+// configuration is invalidated as soon as its file modification time or size changes.
+//
+// The parsed configurations and the config file found by walking up from the working
+// directory are only kept in memory, for the lifetime of the process (e.g. the MCP server
+// or the TUI), the latter until SaveConfig.
+func (s *YAMLStore) WithConfigCache() *YAMLStore {
+	s.cacheConfig = true
+	return s
+}
+
+// cachedConfigPath returns the config file previously found by walking up from the given
+// directory, if it still exists
+func cachedConfigPath(dir string) (string, bool) {
+	configCache.Lock()
+	configPath, ok := configCache.paths[dir]
+	configCache.Unlock()
+
+	if !ok {
+		return "", false
+	}
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err != nil {
+			return "", false
+		}
+	}
+	return configPath, true
+}
+
+// cacheConfigPath remembers the config file found by walking up from the given directory,
+// empty if none was found
+func cacheConfigPath(dir string, configPath string) {
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	configCache.paths[dir] = configPath
+}
+
+// cachedConfig returns a copy of the cached configuration of the given file, if still fresh
+func cachedConfig(configPath string, info os.FileInfo) (*model.Config, bool) {
+	key, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, false
+	}
+
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	entry, ok := configCache.entries[key]
+	if !ok || !entry.isFresh(info) {
+		return nil, false
+	}
+
+	return entry.Config.Clone(), true
+}
+
+// cacheConfig stores a copy of the parsed configuration of the given file
+func cacheConfig(configPath string, info os.FileInfo, config *model.Config) {
+	key, err := filepath.Abs(configPath)
+	if err != nil {
+		return
+	}
+
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	configCache.entries[key] = configCacheEntry{
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Config:  config.Clone(),
+	}
+}
+
+// invalidateConfig removes the cached configuration of the given file, and forgets the
+// config files found by walking up the directories as the file may be a new one
+func invalidateConfig(configPath string) {
+	key, err := filepath.Abs(configPath)
+	if err != nil {
+		return
+	}
+
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	delete(configCache.entries, key)
+	clear(configCache.paths)
+}
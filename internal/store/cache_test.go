@@ -0,0 +1,119 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+// resetConfigCache empties the config cache, as when a new process starts
+func resetConfigCache() {
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	clear(configCache.entries)
+	clear(configCache.paths)
+}
+
+func TestConfigCache(t *testing.T) {
+	t.Cleanup(resetConfigCache)
+
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte("currency: USD\nshowCost: false\ntaskTableColumns: [label, mean]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := NewYAMLStore(path).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	s := NewYAMLStore(path).WithConfigCache()
+	config, err := s.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// Modifying a loaded configuration leaves the cached one untouched
+	config.TaskTableColumns[0] = "cost"
+	*config.ShowCost = true
+
+	t.Run("in memory", func(t *testing.T) {
+		got, err := s.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadConfig() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("invalidated on change", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("currency: GBP\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Make the change visible even on file systems with a coarse modification time
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if got.Currency != "GBP" {
+			t.Errorf("currency = %s, want GBP", got.Currency)
+		}
+	})
+}
+
+func TestConfigCacheRemembersTheWalk(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ConfigEnvVar, "")
+	t.Cleanup(resetConfigCache)
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(root, DefaultConfigFile)
+	if err := os.WriteFile(configPath, []byte("currency: USD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	s := NewYAMLStore("").WithConfigCache()
+	config, err := s.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Currency != "USD" {
+		t.Errorf("currency = %s, want USD", config.Currency)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := cachedConfigPath(wd); !ok || got != configPath {
+		t.Errorf("cached config path = %q, %v, want %q", got, ok, configPath)
+	}
+
+	// A removed config file is not served from the cache
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	config, err = s.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if want := model.DefaultConfig().Currency; config.Currency != want {
+		t.Errorf("currency = %s, want %s", config.Currency, want)
+	}
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotEstimation is returned when loading a YAML document that isn't an estimation
+var ErrNotEstimation = errors.New("this looks like a config file, not an estimation")
+
+// configKeys are the top-level keys of a config file
+var configKeys = yamlKeys(reflect.TypeOf(model.Config{}))
+
+// yamlKeys returns the YAML keys of the fields of the given struct type
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// DecodeEstimation decodes an estimation from YAML data, rejecting config files
+// which would otherwise decode as an empty estimation. A missing task ordering is
+// initialized from the tasks sorted by ID.
+func DecodeEstimation(data []byte) (*model.Estimation, error) {
+	estimation, _, err := decodeEstimation(data)
+	return estimation, err
+}
+
+// decodeEstimation decodes an estimation like DecodeEstimation, also returning true
+// if its task ordering was missing
+func decodeEstimation(data []byte) (*model.Estimation, bool, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, false, err
+	}
+
+	estimation := &model.Estimation{}
+	if len(document.Content) > 0 {
+		root := document.Content[0]
+		if looksLikeConfig(root) {
+			return nil, false, ErrNotEstimation
+		}
+		if err := root.Decode(estimation); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Ensure tasks map is initialized
+	if estimation.Tasks == nil {
+		estimation.Tasks = make(map[model.TaskID]*model.Task)
+	}
+
+	// Ensure ordering is initialized, and populated when tasks exist
+	if estimation.Ordering == nil {
+		estimation.Ordering = []model.TaskID{}
+	}
+	orderingInitialized := estimation.InitOrdering()
+
+	return estimation, orderingInitialized, nil
+}
+
+// decodeConfig decodes a configuration from YAML data, setting the category IDs from
+// their keys and rejecting invalid settings
+func decodeConfig(data []byte) (*model.Config, error) {
+	config := &model.Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	// Set category IDs from map keys
+	for id, cat := range config.TaskCategories {
+		cat.ID = id
+		config.TaskCategories[id] = cat
+	}
+
+	if errors := config.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %s", strings.Join(errors, ", "))
+	}
+
+	return config, nil
+}
+
+// looksLikeConfig sniffs the top-level keys of a YAML document, reporting documents
+// without any estimation identity (id, label or tasks) but with config keys
+func looksLikeConfig(root *yaml.Node) bool {
+	if root.Kind != yaml.MappingNode {
+		return false
+	}
+
+	keys := make([]string, 0, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keys = append(keys, root.Content[i].Value)
+	}
+
+	for _, key := range keys {
+		if key == "id" || key == "label" || key == "tasks" {
+			return false
+		}
+	}
+	for _, key := range keys {
+		if configKeys[key] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeEstimation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+		tasks   int
+	}{
+		{name: "empty"},
+		{name: "config", data: "currency: EUR\ntimeUnit:\n  label: man-day\n  acronym: md\n", wantErr: ErrNotEstimation},
+		{name: "labeled with config keys", data: "label: Project\ncurrency: EUR\n"},
+		{name: "tasks", data: "label: Project\ntasks:\n  a:\n    id: a\n    label: Login\n    category: development\n", tasks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimation, err := DecodeEstimation([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeEstimation() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(estimation.Tasks) != tt.tasks || len(estimation.Ordering) != tt.tasks {
+				t.Errorf("tasks = %d, ordering = %v, want %d tasks", len(estimation.Tasks), estimation.Ordering, tt.tasks)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMaxFileSize is the default maximum size of an estimation file, in bytes
+const DefaultMaxFileSize = 10 << 20
+
+// ErrFileTooLarge is returned when an estimation file exceeds the maximum size of the store
+var ErrFileTooLarge = errors.New("file is too large")
+
+// WithMaxFileSize sets the maximum size of the estimation files read by the store, in bytes.
+// A size <= 0 disables the limit.
+func (s *YAMLStore) WithMaxFileSize(size int64) *YAMLStore {
+	s.maxFileSize = size
+	return s
+}
+
+// ReadFileLimited reads the named file of fsys, failing with ErrFileTooLarge instead of
+// loading it in memory when it exceeds limit bytes. A limit <= 0 disables the check.
+//
+// Nesting and alias expansion ("billion laughs") are already bounded by the YAML decoder,
+// so the size limit is what keeps untrusted files from exhausting memory.
+func ReadFileLimited(fsys fs.FS, name string, limit int64) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if limit <= 0 {
+		return io.ReadAll(f)
+	}
+
+	// Reject files known to be too large without reading them
+	if info, err := f.Stat(); err == nil && info.Size() > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d bytes", ErrFileTooLarge, name, info.Size(), limit)
+	}
+
+	// Read one byte more than the limit to detect files growing while being read
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrFileTooLarge, name, limit)
+	}
+
+	return data, nil
+}
+
+// readFile reads an estimation file within the root directory, if any, and the maximum
+// size of the store
+func (s *YAMLStore) readFile(path string) ([]byte, error) {
+	root, path, err := s.resolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return ReadFileLimited(osFS{}, path, s.maxFileSize)
+	}
+	defer root.Close()
+
+	return ReadFileLimited(root.FS(), filepath.ToSlash(path), s.maxFileSize)
+}
+
+// osFS is a fs.FS over the operating system paths, relative or absolute as given
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
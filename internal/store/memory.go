@@ -0,0 +1,196 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// MemoryStore is an in-memory store, holding the YAML content of the configuration and
+// estimation files by path instead of touching the filesystem
+type MemoryStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryStore creates a new empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		files: make(map[string][]byte),
+	}
+}
+
+// read returns the content of the given file, or an error matching fs.ErrNotExist
+func (s *MemoryStore) read(path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[filepath.Clean(path)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return data, nil
+}
+
+// write sets the content of the given file
+func (s *MemoryStore) write(path string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[filepath.Clean(path)] = data
+}
+
+// LoadConfig loads the configuration, or returns the default one if none was saved
+func (s *MemoryStore) LoadConfig() (*model.Config, error) {
+	data, err := s.read(DefaultConfigFile)
+	if err != nil {
+		return model.DefaultConfig(), nil
+	}
+
+	return decodeConfig(data)
+}
+
+// ConfigFilePath returns the path of the config file written by SaveConfig
+func (s *MemoryStore) ConfigFilePath() string {
+	return DefaultConfigFile
+}
+
+// SaveConfig saves the configuration
+func (s *MemoryStore) SaveConfig(config *model.Config) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	s.write(DefaultConfigFile, data)
+
+	return nil
+}
+
+// LoadEstimation loads an estimation
+func (s *MemoryStore) LoadEstimation(path string) (*model.Estimation, error) {
+	data, err := s.read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeEstimation(data)
+}
+
+// LoadOrCreateEstimation loads an estimation, or creates a new one if it doesn't exist
+func (s *MemoryStore) LoadOrCreateEstimation(path string, label string) (*model.Estimation, bool, error) {
+	estimation, err := s.LoadEstimation(path)
+	if err == nil {
+		return estimation, false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, err
+	}
+
+	estimation, err = s.CreateEstimation(path, label)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return estimation, true, nil
+}
+
+// SaveEstimation saves an estimation
+func (s *MemoryStore) SaveEstimation(path string, estimation *model.Estimation) error {
+	data, err := yaml.Marshal(estimation)
+	if err != nil {
+		return err
+	}
+
+	s.write(path, data)
+
+	return nil
+}
+
+// CreateEstimation creates a new estimation
+func (s *MemoryStore) CreateEstimation(path string, label string) (*model.Estimation, error) {
+	estimation := model.NewEstimation(label)
+
+	if err := s.SaveEstimation(path, estimation); err != nil {
+		return nil, err
+	}
+
+	return estimation, nil
+}
+
+// ListEstimations lists all estimation files directly in a directory, sorted by name
+func (s *MemoryStore) ListEstimations(dir string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+
+	files := []string{}
+	for path := range s.files {
+		if filepath.Dir(path) == dir && IsEstimationFile(path) {
+			files = append(files, filepath.Base(path))
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// WalkEstimations lists all estimation files in a directory and its subdirectories,
+// hidden ones excepted, as paths relative to the directory sorted by name
+func (s *MemoryStore) WalkEstimations(dir string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+
+	files := []string{}
+	for path := range s.files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || !IsEstimationFile(path) {
+			continue
+		}
+		if slices.ContainsFunc(strings.Split(filepath.Dir(rel), string(filepath.Separator)), isHiddenDir) {
+			continue
+		}
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// isHiddenDir returns true if the directory name is a hidden one, e.g. ".git"
+func isHiddenDir(name string) bool {
+	return name != "." && strings.HasPrefix(name, ".")
+}
+
+// DeleteEstimation deletes an estimation
+func (s *MemoryStore) DeleteEstimation(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path = filepath.Clean(path)
+	if _, ok := s.files[path]; !ok {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	delete(s.files, path)
+
+	return nil
+}
+
+// WriteFile writes a file, e.g. an export
+func (s *MemoryStore) WriteFile(path string, data []byte) error {
+	s.write(path, data)
+	return nil
+}
+
+// Ensure MemoryStore implements Store interface
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+// storeFactories create each implementation of Store, along with the directory its
+// estimations are written to
+var storeFactories = map[string]func(t *testing.T) (Store, string){
+	"yaml": func(t *testing.T) (Store, string) {
+		dir := t.TempDir()
+		return NewYAMLStore(filepath.Join(dir, DefaultConfigFile)), dir
+	},
+	"memory": func(t *testing.T) (Store, string) {
+		return NewMemoryStore(), "estimations"
+	},
+}
+
+func TestStore(t *testing.T) {
+	for name, newStore := range storeFactories {
+		t.Run(name, func(t *testing.T) {
+			t.Run("config", func(t *testing.T) {
+				s, _ := newStore(t)
+
+				config, err := s.LoadConfig()
+				if err != nil {
+					t.Fatalf("LoadConfig() error = %v", err)
+				}
+				if !reflect.DeepEqual(config, model.DefaultConfig()) {
+					t.Errorf("LoadConfig() without config = %+v, want the default one", config)
+				}
+
+				config.Currency = "USD"
+				if err := config.AddCategory("design", "Design", 420); err != nil {
+					t.Fatalf("AddCategory() error = %v", err)
+				}
+				if err := s.SaveConfig(config); err != nil {
+					t.Fatalf("SaveConfig() error = %v", err)
+				}
+
+				loaded, err := s.LoadConfig()
+				if err != nil {
+					t.Fatalf("LoadConfig() error = %v", err)
+				}
+				if loaded.Currency != "USD" || loaded.GetTaskCategory("design") != config.TaskCategories["design"] {
+					t.Errorf("LoadConfig() = %+v, want the saved config", loaded)
+				}
+				if s.ConfigFilePath() == "" {
+					t.Errorf("ConfigFilePath() is empty")
+				}
+			})
+
+			t.Run("estimations", func(t *testing.T) {
+				s, dir := newStore(t)
+				path := filepath.Join(dir, "project.estimation.yml")
+
+				if _, err := s.LoadEstimation(path); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("LoadEstimation() of a missing file error = %v, want fs.ErrNotExist", err)
+				}
+
+				created, isNew, err := s.LoadOrCreateEstimation(path, "Project")
+				if err != nil || !isNew {
+					t.Fatalf("LoadOrCreateEstimation() = %v, %v, want a new estimation", isNew, err)
+				}
+				if _, isNew, err := s.LoadOrCreateEstimation(path, "Other"); err != nil || isNew {
+					t.Errorf("LoadOrCreateEstimation() of an existing file = %v, %v, want the existing one", isNew, err)
+				}
+
+				task := model.NewTask("Feature", "development")
+				task.Estimations = model.Estimations{Optimistic: 1.5, Likely: 2, Pessimistic: 4}
+				created.AddTask(task)
+				if err := s.SaveEstimation(path, created); err != nil {
+					t.Fatalf("SaveEstimation() error = %v", err)
+				}
+
+				loaded, err := s.LoadEstimation(path)
+				if err != nil {
+					t.Fatalf("LoadEstimation() error = %v", err)
+				}
+				if loaded.ID != created.ID || loaded.Label != "Project" || len(loaded.Tasks) != 1 || loaded.Tasks[task.ID].Estimations != task.Estimations {
+					t.Errorf("LoadEstimation() = %+v, want the saved estimation", loaded)
+				}
+
+				if _, err := s.CreateEstimation(filepath.Join(dir, "another.estimation.yml"), "Another"); err != nil {
+					t.Fatalf("CreateEstimation() error = %v", err)
+				}
+				files, err := s.ListEstimations(dir)
+				if err != nil {
+					t.Fatalf("ListEstimations() error = %v", err)
+				}
+				if !reflect.DeepEqual(files, []string{"another.estimation.yml", "project.estimation.yml"}) {
+					t.Errorf("ListEstimations() = %v, want both estimations", files)
+				}
+
+				if err := s.DeleteEstimation(path); err != nil {
+					t.Fatalf("DeleteEstimation() error = %v", err)
+				}
+				if _, err := s.LoadEstimation(path); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("LoadEstimation() of a deleted file error = %v, want fs.ErrNotExist", err)
+				}
+				if err := s.DeleteEstimation(path); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("DeleteEstimation() of a missing file error = %v, want fs.ErrNotExist", err)
+				}
+			})
+
+			t.Run("walk", func(t *testing.T) {
+				// The memory store directory is relative to the working directory
+				t.Chdir(t.TempDir())
+				s, dir := newStore(t)
+
+				for _, path := range []string{
+					"project.estimation.yml",
+					filepath.Join("team", "backend.estimation.yml"),
+					filepath.Join(".archive", "old.estimation.yml"),
+					"notes.yml",
+				} {
+					path = filepath.Join(dir, path)
+					if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if _, err := s.CreateEstimation(path, "Project"); err != nil {
+						t.Fatalf("CreateEstimation() error = %v", err)
+					}
+				}
+
+				files, err := s.WalkEstimations(dir)
+				if err != nil {
+					t.Fatalf("WalkEstimations() error = %v", err)
+				}
+				want := []string{"project.estimation.yml", filepath.Join("team", "backend.estimation.yml")}
+				if !reflect.DeepEqual(files, want) {
+					t.Errorf("WalkEstimations() = %v, want %v", files, want)
+				}
+			})
+		})
+	}
+}
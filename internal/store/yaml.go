@@ -1,70 +1,289 @@
 package store
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/bornholm/guesstimate/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrPathOutsideRoot is returned when an estimation path escapes the store root directory
+var ErrPathOutsideRoot = errors.New("path is outside of the root directory")
+
 // YAMLStore handles reading and writing estimation and config files
 type YAMLStore struct {
-	configFile string
+	configFile  string
+	rootDir     string
+	cacheConfig bool
+	maxFileSize int64
+	warn        func(message string)
 }
 
 // NewYAMLStore creates a new YAML store with the given config file path
 func NewYAMLStore(configFile string) *YAMLStore {
 	return &YAMLStore{
-		configFile: configFile,
+		configFile:  configFile,
+		maxFileSize: DefaultMaxFileSize,
+	}
+}
+
+// NewRootedYAMLStore creates a new YAML store whose estimation paths are confined to rootDir.
+// An empty rootDir leaves the store unrestricted.
+func NewRootedYAMLStore(configFile string, rootDir string) *YAMLStore {
+	return &YAMLStore{
+		configFile:  configFile,
+		rootDir:     rootDir,
+		maxFileSize: DefaultMaxFileSize,
+	}
+}
+
+// WithWarnings makes the store report the issues repaired while loading estimations
+// (e.g. a missing task ordering) to the given function
+func (s *YAMLStore) WithWarnings(warn func(message string)) *YAMLStore {
+	s.warn = warn
+	return s
+}
+
+// decodeEstimation decodes the estimation read from path, reporting a missing task ordering
+func (s *YAMLStore) decodeEstimation(path string, data []byte) (*model.Estimation, error) {
+	estimation, orderingInitialized, err := decodeEstimation(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderingInitialized && s.warn != nil {
+		s.warn(fmt.Sprintf("%s: no task ordering, %d tasks ordered by ID", path, len(estimation.Tasks)))
+	}
+
+	return estimation, nil
+}
+
+// resolvePath checks that the given path stays within the store root directory, if any,
+// and returns the opened root along with the path relative to it, to be closed by the
+// caller. Symbolic links are followed so that a link pointing outside the root is rejected
+// too, and the accesses through the root can't escape it should a link change afterwards.
+// Without root directory, the returned root is nil and the path is returned as is.
+func (s *YAMLStore) resolvePath(path string) (*os.Root, string, error) {
+	if s.rootDir == "" {
+		return nil, path, nil
+	}
+
+	rootDir, err := filepath.Abs(s.rootDir)
+	if err != nil {
+		return nil, "", err
+	}
+	rootDir, err = filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve root directory: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rel, err := filepath.Rel(rootDir, evalExistingSymlinks(absPath))
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil, "", fmt.Errorf("%w: %s", ErrPathOutsideRoot, path)
+	}
+
+	root, err := os.OpenRoot(rootDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open root directory: %w", err)
+	}
+
+	return root, rel, nil
+}
+
+// WriteFile writes a file, e.g. an estimation or an export, within the root directory if any
+func (s *YAMLStore) WriteFile(path string, data []byte) error {
+	root, path, err := s.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	defer root.Close()
+
+	return root.WriteFile(path, data, 0644)
+}
+
+// removeFile removes an estimation file, within the root directory if any
+func (s *YAMLStore) removeFile(path string) error {
+	root, path, err := s.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return os.Remove(path)
+	}
+	defer root.Close()
+
+	return root.Remove(path)
+}
+
+// openDir returns the file system of a directory, within the root directory if any, along
+// with the function releasing it
+func (s *YAMLStore) openDir(dir string) (fs.FS, func(), error) {
+	root, dir, err := s.resolvePath(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if root == nil {
+		return os.DirFS(dir), func() {}, nil
+	}
+
+	fsys, err := fs.Sub(root.FS(), filepath.ToSlash(dir))
+	if err != nil {
+		root.Close()
+		return nil, nil, err
+	}
+	return fsys, func() { root.Close() }, nil
+}
+
+// evalExistingSymlinks resolves symbolic links on the longest existing prefix of the
+// given absolute path, keeping the remaining (not yet created) elements as is
+func evalExistingSymlinks(path string) string {
+	existing := path
+	var rest []string
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path
+		}
+		rest = append([]string{filepath.Base(existing)}, rest...)
+		existing = parent
 	}
 }
 
 // DefaultConfigFile returns the default config file name
 const DefaultConfigFile = ".guesstimate.yml"
 
-// LoadConfig loads the configuration from the config file
-// If no specific config file is set, it searches for the config file
-// starting from the current directory and traversing up to parent directories
+// ConfigEnvVar is the environment variable giving an explicit config file path
+const ConfigEnvVar = "GUESSTIMATE_CONFIG"
+
+// LoadConfig loads the configuration from the config file, looking in order for:
+//   - the config file set on the store
+//   - the file given by the GUESSTIMATE_CONFIG environment variable
+//   - the config file in the current directory or its parent directories
+//   - the global $XDG_CONFIG_HOME/guesstimate/config.yml file
+//
+// and falling back to the default configuration if none exists
 func (s *YAMLStore) LoadConfig() (*model.Config, error) {
+	configPath, err := s.resolveConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath == "" {
+		// No config file found, return default config
+		return model.DefaultConfig(), nil
+	}
+
+	return s.loadConfigFromFile(configPath)
+}
+
+// resolveConfigFile returns the config file read by LoadConfig, empty if none exists
+func (s *YAMLStore) resolveConfigFile() (string, error) {
 	// If a specific config file is set, use it directly
 	if s.configFile != "" {
-		return s.loadConfigFromFile(s.configFile)
+		return s.configFile, nil
+	}
+
+	if envConfigFile := os.Getenv(ConfigEnvVar); envConfigFile != "" {
+		return envConfigFile, nil
 	}
 
 	// Search for config file starting from current directory and going up
-	configPath, err := findConfigFile(DefaultConfigFile)
+	configPath, err := s.findConfigFile(DefaultConfigFile)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	if configPath == "" {
-		// No config file found, return default config
-		return model.DefaultConfig(), nil
+		configPath = globalConfigFile()
 	}
 
-	return s.loadConfigFromFile(configPath)
+	return configPath, nil
+}
+
+// ConfigFilePath returns the path of the config file written by SaveConfig: the one read by
+// LoadConfig, so that a parent or global config file is updated rather than shadowed, or
+// the config file of the current directory if none exists
+func (s *YAMLStore) ConfigFilePath() string {
+	configPath, err := s.resolveConfigFile()
+	if err != nil || configPath == "" {
+		return DefaultConfigFile
+	}
+	return configPath
+}
+
+// globalConfigFile returns the path of the global config file if it exists,
+// located in $XDG_CONFIG_HOME (default: ~/.config)
+func globalConfigFile() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+
+	configPath := filepath.Join(configHome, "guesstimate", "config.yml")
+	if _, err := os.Stat(configPath); err != nil {
+		return ""
+	}
+
+	return configPath
 }
 
 // findConfigFile searches for the config file starting from the current directory
-// and traversing up to parent directories until it finds the file or reaches the root
-func findConfigFile(filename string) (string, error) {
+// and traversing up to parent directories until it finds the file or reaches the root.
+// With the config cache enabled, the result of the walk is remembered per directory.
+func (s *YAMLStore) findConfigFile(filename string) (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
 
+	if !s.cacheConfig {
+		return walkUpConfigFile(dir, filename), nil
+	}
+
+	if configPath, ok := cachedConfigPath(dir); ok {
+		return configPath, nil
+	}
+	configPath := walkUpConfigFile(dir, filename)
+	cacheConfigPath(dir, configPath)
+
+	return configPath, nil
+}
+
+// walkUpConfigFile returns the config file found in dir or its parent directories, empty
+// if none exists
+func walkUpConfigFile(dir string, filename string) string {
 	for {
 		configPath := filepath.Join(dir, filename)
 		if _, err := os.Stat(configPath); err == nil {
-			return configPath, nil
+			return configPath
 		}
 
 		// Move to parent directory
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			// Reached the root directory, file not found
-			return "", nil
+			return ""
 		}
 		dir = parent
 	}
@@ -72,6 +291,16 @@ func findConfigFile(filename string) (string, error) {
 
 // loadConfigFromFile loads the configuration from a specific file path
 func (s *YAMLStore) loadConfigFromFile(configPath string) (*model.Config, error) {
+	var info os.FileInfo
+	if s.cacheConfig {
+		var err error
+		if info, err = os.Stat(configPath); err == nil {
+			if config, ok := cachedConfig(configPath, info); ok {
+				return config, nil
+			}
+		}
+	}
+
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -81,64 +310,50 @@ func (s *YAMLStore) loadConfigFromFile(configPath string) (*model.Config, error)
 		return nil, err
 	}
 
-	config := &model.Config{}
-	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, err
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
 	}
 
-	// Set category IDs from map keys
-	for id, cat := range config.TaskCategories {
-		cat.ID = id
-		config.TaskCategories[id] = cat
+	if info != nil {
+		cacheConfig(configPath, info, config)
 	}
 
 	return config, nil
 }
 
-// SaveConfig saves the configuration to the config file
+// SaveConfig saves the configuration to the config file, see ConfigFilePath
 func (s *YAMLStore) SaveConfig(config *model.Config) error {
-	// Use configFile if set, otherwise use default
-	configPath := s.configFile
-	if configPath == "" {
-		configPath = DefaultConfigFile
-	}
+	configPath := s.ConfigFilePath()
 
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return err
 	}
 
+	invalidateConfig(configPath)
+
 	return os.WriteFile(configPath, data, 0644)
 }
 
 // LoadEstimation loads an estimation from a file
 func (s *YAMLStore) LoadEstimation(path string) (*model.Estimation, error) {
-	data, err := os.ReadFile(path)
+	data, err := s.readFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	estimation := &model.Estimation{}
-	if err := yaml.Unmarshal(data, estimation); err != nil {
+	estimation, err := s.decodeEstimation(path, data)
+	if err != nil {
 		return nil, err
 	}
 
-	// Ensure tasks map is initialized
-	if estimation.Tasks == nil {
-		estimation.Tasks = make(map[model.TaskID]*model.Task)
-	}
-
-	// Ensure ordering is initialized
-	if estimation.Ordering == nil {
-		estimation.Ordering = []model.TaskID{}
-	}
-
 	return estimation, nil
 }
 
 // LoadOrCreateEstimation loads an estimation from a file, or creates a new one if it doesn't exist
 func (s *YAMLStore) LoadOrCreateEstimation(path string, label string) (*model.Estimation, bool, error) {
-	data, err := os.ReadFile(path)
+	data, err := s.readFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Create new estimation
@@ -151,32 +366,24 @@ func (s *YAMLStore) LoadOrCreateEstimation(path string, label string) (*model.Es
 		return nil, false, err
 	}
 
-	estimation := &model.Estimation{}
-	if err := yaml.Unmarshal(data, estimation); err != nil {
+	estimation, err := s.decodeEstimation(path, data)
+	if err != nil {
 		return nil, false, err
 	}
 
-	// Ensure tasks map is initialized
-	if estimation.Tasks == nil {
-		estimation.Tasks = make(map[model.TaskID]*model.Task)
-	}
-
-	// Ensure ordering is initialized
-	if estimation.Ordering == nil {
-		estimation.Ordering = []model.TaskID{}
-	}
-
 	return estimation, false, nil
 }
 
-// SaveEstimation saves an estimation to a file
+// SaveEstimation saves an estimation to a file. The estimates are written unrounded,
+// whatever the rounding configuration, with the shortest representation loading back
+// the exact same values.
 func (s *YAMLStore) SaveEstimation(path string, estimation *model.Estimation) error {
 	data, err := yaml.Marshal(estimation)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return s.WriteFile(path, data)
 }
 
 // CreateEstimation creates a new estimation file
@@ -192,7 +399,13 @@ func (s *YAMLStore) CreateEstimation(path string, label string) (*model.Estimati
 
 // ListEstimations lists all estimation files in a directory
 func (s *YAMLStore) ListEstimations(dir string) ([]string, error) {
-	entries, err := os.ReadDir(dir)
+	fsys, release, err := s.openDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []string{}, nil
@@ -202,25 +415,88 @@ func (s *YAMLStore) ListEstimations(dir string) ([]string, error) {
 
 	var files []string
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".yml" {
-			// Check if it's an estimation file (ends with .estimation.yml)
-			if filepath.Ext(filepath.Base(entry.Name()[:len(entry.Name())-4])) == ".estimation" {
-				files = append(files, entry.Name())
+		if !entry.IsDir() && IsEstimationFile(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+
+	return files, nil
+}
+
+// WalkEstimations lists all estimation files in a directory and its subdirectories,
+// hidden ones excepted, as paths relative to the directory sorted by name
+func (s *YAMLStore) WalkEstimations(dir string) ([]string, error) {
+	fsys, release, err := s.openDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return walkEstimations(fsys)
+}
+
+// walkEstimations lists all estimation files of a file system, hidden directories excepted
+func walkEstimations(fsys fs.FS) ([]string, error) {
+	files := []string{}
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == "." && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != "." && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
 			}
+			return nil
+		}
+		if IsEstimationFile(entry.Name()) {
+			files = append(files, filepath.FromSlash(path))
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return files, nil
 }
 
-// Store interface for dependency injection
+// DeleteEstimation deletes an estimation file
+func (s *YAMLStore) DeleteEstimation(path string) error {
+	return s.removeFile(path)
+}
+
+// IsEstimationFile returns true if the file name ends with .estimation.yml or .estimation.yaml
+func IsEstimationFile(name string) bool {
+	ext := filepath.Ext(name)
+	if ext != ".yml" && ext != ".yaml" {
+		return false
+	}
+	return filepath.Ext(strings.TrimSuffix(name, ext)) == ".estimation"
+}
+
+// EstimationFileName returns the file name of a new estimation with the given name,
+// e.g. "my-project.estimation.yml" for "My Project"
+func EstimationFileName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-")) + ".estimation.yml"
+}
+
+// Store interface for dependency injection, implemented by YAMLStore on the filesystem
+// and by MemoryStore in memory
 type Store interface {
 	LoadConfig() (*model.Config, error)
 	SaveConfig(config *model.Config) error
+	ConfigFilePath() string
 	LoadEstimation(path string) (*model.Estimation, error)
+	LoadOrCreateEstimation(path string, label string) (*model.Estimation, bool, error)
 	SaveEstimation(path string, estimation *model.Estimation) error
 	CreateEstimation(path string, label string) (*model.Estimation, error)
 	ListEstimations(dir string) ([]string, error)
+	WalkEstimations(dir string) ([]string, error)
+	DeleteEstimation(path string) error
+	WriteFile(path string, data []byte) error
 }
 
 // Ensure YAMLStore implements Store interface
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bornholm/guesstimate/internal/model"
+)
+
+// roundTripEstimation exercises the fields whose values must survive a save unchanged,
+// estimates with more digits than displayed and unknown keys included
+const roundTripEstimation = `id: e1
+label: Round trip
+description: |
+  Multi-line
+  description
+owner: Jane
+teamSize: 3
+tags: [q1, client]
+locked: true
+approvedBy: Jane
+approvedAt: 2026-01-02T03:04:05Z
+createdAt: 2026-01-01T00:00:00Z
+updatedAt: 2026-01-02T00:00:00.123456789Z
+ordering: [t2, t1]
+tasks:
+  t1:
+    id: t1
+    label: Precise
+    category: development
+    estimations:
+      optimistic: 1.23456789
+      likely: 2.25
+      pessimistic: 10.0000001
+    maxEstimate: 7.5
+    confidence: low
+    fixedCost: 99.99
+    tags: [must-have]
+    createdAt: 2026-01-01T00:00:00Z
+    updatedAt: 2026-01-01T12:00:00Z
+    custom: kept
+  t2:
+    id: t2
+    label: Tiny
+    category: testing
+    estimations:
+      optimistic: 0.1
+      likely: 0.2
+      pessimistic: 0.30000000000000004
+params:
+  currency: USD
+  roundUpEstimations: false
+  timeUnit:
+    label: man-hour
+    acronym: h
+  taskCategories:
+    development:
+      label: Dev
+      costPerTimeUnit: 80.5
+extra:
+  nested: [1, 2]
+`
+
+func TestSaveEstimationRoundTrip(t *testing.T) {
+	tests := []struct {
+		name              string
+		roundUp           bool
+		roundingDirection string
+	}{
+		{name: "no rounding"},
+		{name: "rounded up", roundUp: true, roundingDirection: model.RoundingDirectionUp},
+		{name: "rounded bounds", roundUp: true, roundingDirection: model.RoundingDirectionBounds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, DefaultConfigFile)
+			config := fmt.Sprintf("roundUpEstimations: %t\nroundingDirection: %s\n", tt.roundUp, tt.roundingDirection)
+			if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			s := NewYAMLStore(configPath)
+			if _, err := s.LoadConfig(); err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			path := filepath.Join(dir, "round-trip.estimation.yml")
+
+			// The estimation rounds its estimates too, on top of the configuration
+			fixture := strings.Replace(roundTripEstimation, "roundUpEstimations: false", fmt.Sprintf("roundUpEstimations: %t", tt.roundUp), 1)
+			decoded, err := DecodeEstimation([]byte(fixture))
+			if err != nil {
+				t.Fatalf("failed to decode estimation: %v", err)
+			}
+
+			if err := s.SaveEstimation(path, decoded); err != nil {
+				t.Fatalf("failed to save estimation: %v", err)
+			}
+
+			loaded, err := s.LoadEstimation(path)
+			if err != nil {
+				t.Fatalf("failed to load estimation: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, loaded) {
+				t.Errorf("estimation changed by a save:\n got %+v\nwant %+v", loaded, decoded)
+			}
+
+			// The estimates are stored unrounded
+			if got := loaded.Tasks["t1"].Estimations; got.Optimistic != 1.23456789 || got.Likely != 2.25 || got.Pessimistic != 10.0000001 {
+				t.Errorf("estimates = %+v, want 1.23456789/2.25/10.0000001", got)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejectsInvalidSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("currency: EUR\ndiscount: 150\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewYAMLStore(path).LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "discount must be between 0 and 100") {
+		t.Errorf("LoadConfig() error = %v, want the invalid discount", err)
+	}
+}
+
+func TestSaveConfigWritesBackToTheLoadedFile(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(ConfigEnvVar, "")
+	t.Cleanup(resetConfigCache)
+
+	globalHome := t.TempDir()
+	globalConfig := filepath.Join(globalHome, "guesstimate", "config.yml")
+	if err := os.MkdirAll(filepath.Dir(globalConfig), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "project")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	parentConfig := filepath.Join(root, DefaultConfigFile)
+
+	tests := []struct {
+		name       string
+		configFile string
+		want       string
+	}{
+		{name: "parent directory", configFile: parentConfig, want: parentConfig},
+		{name: "global", configFile: globalConfig, want: globalConfig},
+		{name: "none", want: filepath.Join(dir, DefaultConfigFile)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", globalHome)
+			t.Chdir(dir)
+			resetConfigCache()
+			for _, path := range []string{parentConfig, globalConfig, filepath.Join(dir, DefaultConfigFile)} {
+				os.Remove(path)
+			}
+			if tt.configFile != "" {
+				if err := os.WriteFile(tt.configFile, []byte("currency: USD\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := NewYAMLStore("").WithConfigCache()
+			config, err := s.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			config.Currency = "GBP"
+			if err := s.SaveConfig(config); err != nil {
+				t.Fatalf("SaveConfig() error = %v", err)
+			}
+
+			data, err := os.ReadFile(tt.want)
+			if err != nil {
+				t.Fatalf("config not written to %s: %v", tt.want, err)
+			}
+			if !strings.Contains(string(data), "currency: GBP") {
+				t.Errorf("%s = %s, want the saved currency", tt.want, data)
+			}
+			if tt.configFile != "" {
+				if _, err := os.Stat(filepath.Join(dir, DefaultConfigFile)); err == nil {
+					t.Errorf("SaveConfig() created a config file shadowing %s", tt.configFile)
+				}
+			}
+		})
+	}
+}
+
+func TestRootedYAMLStore(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "estimations")
+	outside := filepath.Join(dir, "outside.estimation.yml")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewYAMLStore("").SaveEstimation(outside, model.NewEstimation("Outside")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link.estimation.yml")); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	s := NewRootedYAMLStore("", "estimations")
+
+	path := filepath.Join("estimations", "project.estimation.yml")
+	if _, err := s.CreateEstimation(path, "Project"); err != nil {
+		t.Fatalf("CreateEstimation() error = %v", err)
+	}
+	for _, path := range []string{path, filepath.Join(root, "project.estimation.yml")} {
+		if _, err := s.LoadEstimation(path); err != nil {
+			t.Errorf("LoadEstimation(%s) error = %v", path, err)
+		}
+	}
+
+	files, err := s.ListEstimations("estimations")
+	if err != nil {
+		t.Fatalf("ListEstimations() error = %v", err)
+	}
+	if want := []string{"link.estimation.yml", "project.estimation.yml"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ListEstimations() = %v, want %v", files, want)
+	}
+
+	for _, path := range []string{
+		"outside.estimation.yml",
+		filepath.Join("estimations", "..", "outside.estimation.yml"),
+		filepath.Join("estimations", "link.estimation.yml"),
+	} {
+		if _, err := s.LoadEstimation(path); !errors.Is(err, ErrPathOutsideRoot) {
+			t.Errorf("LoadEstimation(%s) error = %v, want ErrPathOutsideRoot", path, err)
+		}
+		if err := s.SaveEstimation(path, model.NewEstimation("Escaped")); !errors.Is(err, ErrPathOutsideRoot) {
+			t.Errorf("SaveEstimation(%s) error = %v, want ErrPathOutsideRoot", path, err)
+		}
+		if err := s.WriteFile(path, []byte("# Export\n")); !errors.Is(err, ErrPathOutsideRoot) {
+			t.Errorf("WriteFile(%s) error = %v, want ErrPathOutsideRoot", path, err)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/bornholm/guesstimate/internal/editor"
+	"github.com/bornholm/guesstimate/internal/format"
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/bornholm/guesstimate/internal/stats"
 	"github.com/bornholm/guesstimate/internal/store"
@@ -15,13 +23,14 @@ import (
 type App struct {
 	app        *tview.Application
 	store      store.Store
+	editor     *editor.EstimationEditor
 	config     *model.Config
 	estimation *model.Estimation
-	filePath   string
 
 	// UI Components
 	pages      *tview.Pages
 	layout     *tview.Flex
+	content    *tview.Flex
 	header     *tview.TextView
 	taskTable  *TaskTable
 	preview    *tview.TextView
@@ -29,19 +38,49 @@ type App struct {
 	commandBar *tview.InputField
 
 	// State
-	hasUnsavedChanges bool
-	commandMode       bool
-	modalVisible      bool
+	bindings         []keyBinding
+	commandMode      bool
+	modalVisible     bool
+	targetConfidence float64
+	screenWidth      int
+	screenHeight     int
+	resizeModal      func() // fits the visible modal to the terminal size
+	previewTimer     *time.Timer
+	ctrlCPressed     bool // Ctrl+C was pressed once with unsaved changes
+
+	// Commands entered in the command bar during the session, the oldest first,
+	// historyIndex being the recalled entry (len(commandHistory) for the draft)
+	commandHistory []string
+	historyIndex   int
+	commandDraft   string
 }
 
+// categoryColors is the palette used to draw the category bars of the preview
+var categoryColors = []string{"green", "blue", "orange", "purple", "teal", "red", "yellow", "fuchsia"}
+
+// Bounds and step of the adjustable target confidence of the preview, in percent
+const (
+	defaultTargetConfidence = 80
+	minTargetConfidence     = 5
+	maxTargetConfidence     = 95
+	targetConfidenceStep    = 5
+)
+
+// singleColumnWidth is the terminal width below which the preview is displayed
+// under the tasks table rather than next to it
+const singleColumnWidth = 100
+
 // NewApp creates a new App instance
 func NewApp(s store.Store, config *model.Config, estimation *model.Estimation, filePath string) *App {
 	a := &App{
 		app:        tview.NewApplication(),
 		store:      s,
+		editor:     editor.NewEstimationEditor(s, config, estimation, filePath),
 		config:     config,
 		estimation: estimation,
-		filePath:   filePath,
+
+		targetConfidence: defaultTargetConfidence,
+		bindings:         appKeyBindings(),
 	}
 
 	a.setupUI()
@@ -59,15 +98,11 @@ func (a *App) setupUI() {
 
 	// Task table
 	a.taskTable = NewTaskTable(a.estimation, a.config)
-	a.taskTable.OnTaskChanged = a.onTaskChanged
-	a.taskTable.OnTaskAdded = a.onTaskAdded
-	a.taskTable.OnTaskRemoved = a.onTaskRemoved
 
 	// Preview
 	a.preview = tview.NewTextView()
 	a.preview.SetDynamicColors(true)
 	a.preview.SetBorder(true)
-	a.preview.SetTitle(" Estimation Preview ")
 	a.updatePreview()
 
 	// Command bar (hidden by default)
@@ -75,21 +110,37 @@ func (a *App) setupUI() {
 	a.commandBar.SetLabel(":")
 	a.commandBar.SetFieldWidth(40)
 	a.commandBar.SetDoneFunc(a.handleCommand)
+	a.commandBar.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			a.recallCommand(-1)
+			return nil
+		case tcell.KeyDown:
+			a.recallCommand(1)
+			return nil
+		}
+		return event
+	})
 
 	// Footer
 	a.footer = tview.NewTextView()
 	a.footer.SetDynamicColors(true)
 	a.updateFooter()
 
-	// Main content (two columns)
-	mainContent := tview.NewFlex().SetDirection(tview.FlexColumn)
-	mainContent.AddItem(a.taskTable, 0, 3, true) // Left: tasks table (3/4 width)
-	mainContent.AddItem(a.preview, 0, 1, false)  // Right: estimation preview (1/4 width)
+	// Columns of the task table, the default ones being kept if the configured ones are invalid
+	if err := a.taskTable.SetColumns(a.config.TaskTableColumns); err != nil {
+		a.showError(fmt.Errorf("invalid taskTableColumns: %w", err))
+	}
+
+	// Main content (two columns, collapsed to one on narrow terminals)
+	a.content = tview.NewFlex().SetDirection(tview.FlexColumn)
+	a.content.AddItem(a.taskTable, 0, 3, true) // Left: tasks table (3/4 width)
+	a.content.AddItem(a.preview, 0, 1, false)  // Right: estimation preview (1/4 width)
 
 	// Layout
 	a.layout = tview.NewFlex().SetDirection(tview.FlexRow)
 	a.layout.AddItem(a.header, 3, 0, false)
-	a.layout.AddItem(mainContent, 0, 1, true)
+	a.layout.AddItem(a.content, 0, 1, true)
 	a.layout.AddItem(a.footer, 1, 0, false)
 
 	// Pages for modal dialogs
@@ -104,21 +155,64 @@ func (a *App) updateFooter() {
 
 // Run starts the application
 func (a *App) Run() error {
+	return a.RunContext(context.Background())
+}
+
+// RunContext starts the application and stops it when the context is cancelled
+func (a *App) RunContext(ctx context.Context) error {
 	// Set up input capture on the pages (not layout)
 	a.pages.SetInputCapture(a.handleInput)
 
-	// Prevent Ctrl+C from quitting the app
+	// Prevent Ctrl+C from quitting the app, unless the trap is disabled
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyCtrlC {
+		if event.Key() != tcell.KeyCtrlC {
+			a.ctrlCPressed = false
+			return event
+		}
+		if a.config.GetTrapCtrlC() {
 			// Ignore Ctrl+C, user must use :q or :q! to quit
 			return nil
 		}
-		return event
+		a.interrupt()
+		return nil
+	})
+
+	// Adapt the layout, the modal and the preview bars when the terminal is resized
+	a.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		if width, height := screen.Size(); width != a.screenWidth || height != a.screenHeight {
+			a.screenWidth, a.screenHeight = width, height
+			a.updateLayout()
+			if a.modalVisible && a.resizeModal != nil {
+				a.resizeModal()
+			}
+			a.updatePreview()
+		}
+		return false
 	})
 
 	a.app.SetRoot(a.pages, true)
 	a.app.SetFocus(a.taskTable)
-	return a.app.Run()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Stop the application when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			a.app.Stop()
+		case <-done:
+		}
+	}()
+
+	if err := a.app.Run(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
 }
 
 // handleInput handles global key input
@@ -133,32 +227,22 @@ func (a *App) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		return event
 	}
 
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case ':':
-			// Start command mode
-			a.startCommandMode()
-			return nil
-		case '?':
-			a.showHelp()
-			return nil
-		case 'a':
-			a.addNewTask()
-			return nil
-		case 'e', 'i':
-			a.editSelectedTask()
-			return nil
-		case 'd':
-			a.deleteSelectedTask()
-			return nil
-		case 'J':
-			a.moveTaskDown()
-			return nil
-		case 'K':
-			a.moveTaskUp()
-			return nil
+	// While a task is grabbed, only moving, dropping and cancelling are allowed
+	if a.taskTable.IsGrabbing() {
+		if binding, ok := findKeyBinding(a.bindings, keyModeGrab, event); ok {
+			binding.app(a)
+		} else if _, ok := findKeyBinding(a.taskTable.bindings, keyModeGrab, event); ok {
+			return event
 		}
+		return nil
+	}
+
+	// Clear any transient message
+	a.updateFooter()
+
+	if binding, ok := findKeyBinding(a.bindings, keyModeNormal, event); ok {
+		binding.app(a)
+		return nil
 	}
 
 	// Pass through to task table for navigation
@@ -169,6 +253,8 @@ func (a *App) handleInput(event *tcell.EventKey) *tcell.EventKey {
 func (a *App) startCommandMode() {
 	a.commandMode = true
 	a.commandBar.SetText("")
+	a.historyIndex = len(a.commandHistory)
+	a.commandDraft = ""
 
 	// Replace footer with command bar
 	a.layout.RemoveItem(a.footer)
@@ -195,13 +281,54 @@ func (a *App) handleCommand(key tcell.Key) {
 	}
 
 	command := strings.TrimSpace(a.commandBar.GetText())
+	a.addToHistory(command)
+
+	if args := strings.Fields(command); len(args) > 0 && args[0] == "export" {
+		if len(args) != 3 {
+			a.commandBar.SetText("[red]Error: Usage: export <markdown|json|yaml> <file>[white]")
+			return
+		}
+		a.export(args[1], args[2])
+		return
+	}
+
+	if args := strings.Fields(command); len(args) > 0 && args[0] == "rename" {
+		label := strings.TrimSpace(strings.TrimPrefix(command, args[0]))
+		if label == "" {
+			a.commandBar.SetText("[red]Error: Usage: rename <label>[white]")
+			return
+		}
+		if err := a.editor.Rename(label); err != nil {
+			a.commandBar.SetText(fmt.Sprintf("[red]Error: %v[white]", err))
+			return
+		}
+		a.onEstimationChanged()
+		a.exitCommandMode()
+		return
+	}
+
+	if args := strings.Fields(command); len(args) > 0 && args[0] == "columns" {
+		// Columns separated by commas or spaces, none restoring the configured ones
+		ids := strings.FieldsFunc(strings.TrimPrefix(command, args[0]), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(ids) == 0 {
+			ids = a.config.TaskTableColumns
+		}
+		if err := a.taskTable.SetColumns(ids); err != nil {
+			a.commandBar.SetText(fmt.Sprintf("[red]Error: %v[white]", err))
+			return
+		}
+		a.exitCommandMode()
+		return
+	}
 
 	switch command {
 	case "w":
 		a.save()
 		a.exitCommandMode()
 	case "q":
-		if a.hasUnsavedChanges {
+		if a.editor.HasUnsavedChanges() {
 			// Show error in command bar, don't exit
 			a.commandBar.SetText("[red]Error: Unsaved changes. Use :q! to force quit.[white]")
 			a.commandBar.SetLabel(":")
@@ -210,8 +337,11 @@ func (a *App) handleCommand(key tcell.Key) {
 		}
 	case "q!":
 		a.app.Stop()
+	case "describe":
+		a.exitCommandMode()
+		a.describeEstimation()
 	case "wq", "x":
-		if err := a.store.SaveEstimation(a.filePath, a.estimation); err == nil {
+		if err := a.editor.Save(); err == nil {
 			a.app.Stop()
 		} else {
 			a.commandBar.SetText(fmt.Sprintf("[red]Error: Failed to save: %v[white]", err))
@@ -222,10 +352,48 @@ func (a *App) handleCommand(key tcell.Key) {
 	}
 }
 
+// addToHistory records an entered command, skipping empty ones and immediate repeats
+func (a *App) addToHistory(command string) {
+	if command != "" && (len(a.commandHistory) == 0 || a.commandHistory[len(a.commandHistory)-1] != command) {
+		a.commandHistory = append(a.commandHistory, command)
+	}
+	a.historyIndex = len(a.commandHistory)
+	a.commandDraft = ""
+}
+
+// recallCommand replaces the command bar text with an older (offset < 0) or newer
+// (offset > 0) command of the history, going past the newest one restoring the draft
+func (a *App) recallCommand(offset int) {
+	index := max(0, min(a.historyIndex+offset, len(a.commandHistory)))
+	if index == a.historyIndex {
+		return
+	}
+
+	// Keep what was being typed before browsing the history
+	if a.historyIndex == len(a.commandHistory) {
+		a.commandDraft = a.commandBar.GetText()
+	}
+
+	a.historyIndex = index
+	if index == len(a.commandHistory) {
+		a.commandBar.SetText(a.commandDraft)
+	} else {
+		a.commandBar.SetText(a.commandHistory[index])
+	}
+}
+
+// refuseIfLocked shows a message in the footer and returns true if the estimation is locked
+func (a *App) refuseIfLocked() bool {
+	if err := a.estimation.CheckUnlocked(); err != nil {
+		a.footer.SetText(fmt.Sprintf("[red]Error: %v (guesstimate unlock <file>)[white]", err))
+		return true
+	}
+	return false
+}
+
 // deleteSelectedTask deletes the currently selected task
 func (a *App) deleteSelectedTask() {
-	row, _ := a.taskTable.GetSelection()
-	if row < 1 || row > a.taskTable.GetTaskCount() {
+	if a.refuseIfLocked() {
 		return
 	}
 
@@ -235,51 +403,118 @@ func (a *App) deleteSelectedTask() {
 	}
 
 	// Delete directly without confirmation
-	a.estimation.RemoveTask(task.ID)
+	if err := a.editor.RemoveTask(task.ID); err != nil {
+		a.showError(err)
+		return
+	}
 	a.taskTable.Refresh()
-	a.hasUnsavedChanges = true
-	a.updateHeader()
-	a.updatePreview()
+	a.onEstimationChanged()
 }
 
 // moveTaskUp moves the selected task up
 func (a *App) moveTaskUp() {
-	row, _ := a.taskTable.GetSelection()
-	if row < 2 {
+	a.moveSelectedTask(-1)
+}
+
+// moveTaskDown moves the selected task down
+func (a *App) moveTaskDown() {
+	a.moveSelectedTask(1)
+}
+
+// moveSelectedTask moves the selected task by the given offset, keeping it selected
+func (a *App) moveSelectedTask(offset int) {
+	if a.refuseIfLocked() {
 		return
 	}
 
+	row, _ := a.taskTable.GetSelection()
 	task := a.taskTable.GetSelectedTask()
 	if task == nil {
 		return
 	}
 
-	a.estimation.MoveTask(task.ID, -1)
+	moved, err := a.editor.MoveTask(task.ID, offset)
+	if err != nil {
+		a.showError(err)
+		return
+	}
+	if !moved {
+		return
+	}
+
 	a.taskTable.Refresh()
-	a.hasUnsavedChanges = true
-	a.updateHeader()
-	a.updatePreview()
-	a.taskTable.Select(row-1, 0)
+	a.onEstimationChanged()
+	a.taskTable.Select(row+offset, 0)
 }
 
-// moveTaskDown moves the selected task down
-func (a *App) moveTaskDown() {
-	row, _ := a.taskTable.GetSelection()
-	if row >= a.taskTable.GetTaskCount() {
+// grabSelectedTask grabs the selected task to move it over several rows at once
+func (a *App) grabSelectedTask() {
+	if a.refuseIfLocked() {
 		return
 	}
 
-	task := a.taskTable.GetSelectedTask()
+	if !a.taskTable.Grab() {
+		return
+	}
+
+	a.footer.SetText("[yellow]GRAB[white]  [yellow]j/k[white] Move  [yellow]Space[white] Drop  [yellow]Esc[white] Cancel")
+}
+
+// dropGrabbedTask releases the grabbed task, committing its new position
+func (a *App) dropGrabbedTask() {
+	a.updateFooter()
+
+	task, origin, row := a.taskTable.Release()
 	if task == nil {
 		return
 	}
 
-	a.estimation.MoveTask(task.ID, 1)
+	moved, err := a.editor.MoveTask(task.ID, row-origin)
+	if err != nil {
+		a.showError(err)
+	}
+
 	a.taskTable.Refresh()
-	a.hasUnsavedChanges = true
+	if moved {
+		a.taskTable.Select(row, 0)
+		a.onEstimationChanged()
+	} else {
+		a.taskTable.Select(origin, 0)
+	}
+}
+
+// cancelGrab releases the grabbed task, leaving it at its position
+func (a *App) cancelGrab() {
+	a.taskTable.CancelGrab()
+	a.updateFooter()
+}
+
+// showError shows an error in the footer until the next key press
+func (a *App) showError(err error) {
+	a.footer.SetText(fmt.Sprintf("[red]Error: %v[white]", tview.Escape(err.Error())))
+}
+
+// refresh recomputes and repaints the whole display from the in-memory estimation,
+// clearing any transient message
+func (a *App) refresh() {
+	row, _ := a.taskTable.GetSelection()
+
+	a.taskTable.Refresh()
+	if count := a.taskTable.GetTaskCount(); count > 0 {
+		a.taskTable.Select(max(1, min(row, count)), 0)
+	}
+
+	a.commandBar.SetText("")
 	a.updateHeader()
 	a.updatePreview()
-	a.taskTable.Select(row+1, 0)
+	a.updateFooter()
+	a.app.Sync()
+}
+
+// adjustTargetConfidence changes the target confidence of the preview by the given delta
+func (a *App) adjustTargetConfidence(delta float64) {
+	a.targetConfidence = max(minTargetConfidence, min(a.targetConfidence+delta, maxTargetConfidence))
+	a.updatePreview()
 }
 
 // updateHeader updates the header text
@@ -290,102 +525,194 @@ func (a *App) updateHeader() {
 	}
 
 	saved := ""
-	if a.hasUnsavedChanges {
+	if a.editor.HasUnsavedChanges() {
 		saved = " [red](unsaved changes)[white]"
 	}
+	if a.estimation.Locked {
+		saved += " [yellow](locked)[white]"
+	}
 
 	a.header.SetTitle(fmt.Sprintf(" Guesstimate - %s%s ", title, saved))
+	// Only the first line of the description fits in the header
+	description, _, _ := strings.Cut(a.estimation.Description, "\n")
+	a.header.SetText(tview.Escape(description))
 	a.header.SetBorder(true)
 }
 
+// schedulePreviewUpdate updates the preview once edits settle, coalescing rapid
+// successive edits into a single recompute after the configured debounce interval
+func (a *App) schedulePreviewUpdate() {
+	debounce := a.config.GetPreviewDebounce()
+	if debounce <= 0 {
+		a.updatePreview()
+		return
+	}
+
+	if a.previewTimer != nil {
+		a.previewTimer.Stop()
+	}
+	a.previewTimer = time.AfterFunc(debounce, func() {
+		a.app.QueueUpdateDraw(a.updatePreview)
+	})
+}
+
 // updatePreview updates the estimation preview
 func (a *App) updatePreview() {
 	var sb strings.Builder
 
-	projectEst := stats.CalculateProjectEstimation(a.estimation)
+	projectEst := stats.CalculateProjectEstimationFor(a.estimation, a.config)
 	roundUp := a.config.RoundUpEstimations
 
 	sb.WriteString(fmt.Sprintf("[yellow]Tasks:[white] %d\n\n", len(a.estimation.Tasks)))
 
+	// Target confidence
+	target := stats.ConfidenceForLevel(a.targetConfidence)
+	a.preview.SetTitle(fmt.Sprintf(" Estimation Preview (%s) ", target.Name))
+
+	if projectEst.IsEmpty() {
+		sb.WriteString("[gray]No estimates yet[white]")
+		a.writePreviewWarnings(&sb)
+		a.preview.SetText(sb.String())
+		return
+	}
+
 	sb.WriteString("[yellow]Time Estimation:[white]\n")
-	sb.WriteString(fmt.Sprintf("  99.7%%: %s ± %s %s\n",
-		formatFloat(projectEst.WeightedMean, roundUp),
-		formatFloat(projectEst.StandardDeviation*3, roundUp),
-		a.config.TimeUnit.Acronym))
-	sb.WriteString(fmt.Sprintf("  90%%:   %s ± %s %s\n",
-		formatFloat(projectEst.WeightedMean, roundUp),
-		formatFloat(projectEst.StandardDeviation*1.645, roundUp),
-		a.config.TimeUnit.Acronym))
-	sb.WriteString(fmt.Sprintf("  68%%:   %s ± %s %s\n",
-		formatFloat(projectEst.WeightedMean, roundUp),
-		formatFloat(projectEst.StandardDeviation, roundUp),
+	for _, cl := range stats.GetConfidenceLevels(a.config) {
+		sb.WriteString(fmt.Sprintf("  %-6s %s ± %s %s\n",
+			cl.Name+":",
+			formatFloat(projectEst.WeightedMean, roundUp),
+			formatFloat(projectEst.StandardDeviation*cl.Multiplier, roundUp),
+			a.config.TimeUnit.Acronym))
+	}
+
+	// Adjustable target confidence
+	sb.WriteString(fmt.Sprintf("\n[yellow]Target (%s):[white] [gray](+/-)[white]\n", target.Name))
+	sb.WriteString(fmt.Sprintf("  %s - %s %s\n",
+		formatFloat(projectEst.WeightedMean-projectEst.StandardDeviation*target.Multiplier, roundUp),
+		formatFloat(projectEst.WeightedMean+projectEst.StandardDeviation*target.Multiplier, roundUp),
 		a.config.TimeUnit.Acronym))
 
 	// Category distribution
 	distribution := stats.CalculateCategoryDistribution(a.estimation, a.config)
 	if len(distribution) > 0 {
 		sb.WriteString("\n[yellow]Category Repartition:[white]\n")
-		for _, dist := range distribution {
+		barWidth := a.previewBarWidth()
+		for i, dist := range stats.SortByShare(distribution) {
 			if dist.Percentage > 0 {
 				sb.WriteString(fmt.Sprintf("  %s: %.1f%% (%s %s)\n",
 					dist.CategoryLabel,
 					dist.Percentage,
-					formatFloat(dist.Time, roundUp),
+					formatFloat(dist.Time, a.config.RoundUpParts()),
 					a.config.TimeUnit.Acronym))
+				if barWidth > 0 {
+					sb.WriteString(fmt.Sprintf("  [%s]%s[white]\n",
+						categoryColors[i%len(categoryColors)],
+						renderBar(dist.Percentage/100, barWidth)))
+				}
 			}
 		}
 	}
 
-	costs := stats.CalculateMinMaxCosts(a.estimation, a.config, stats.Confidence997)
-	sb.WriteString(fmt.Sprintf("\n[yellow]Cost (99.7%%):[white]\n"))
-	sb.WriteString(fmt.Sprintf("  Max: %s %s (%s %s)\n",
-		formatFloat(costs.Max.TotalCost, false), a.config.Currency,
-		formatFloat(costs.Max.TotalTime, roundUp), a.config.TimeUnit.Acronym))
-	sb.WriteString(fmt.Sprintf("  Min: %s %s (%s %s)",
-		formatFloat(costs.Min.TotalCost, false), a.config.Currency,
-		formatFloat(costs.Min.TotalTime, roundUp), a.config.TimeUnit.Acronym))
+	if a.config.HasCosts() {
+		costConfidence := stats.CostConfidenceLevel(a.config)
+		costs := stats.CalculateMinMaxCostsFrom(projectEst, distribution, stats.CalculateFixedCost(a.estimation), a.config, costConfidence)
+		sb.WriteString(fmt.Sprintf("\n[yellow]Cost (%s):[white]\n", costConfidence.Name))
+		sb.WriteString(fmt.Sprintf("  Max: %s %s (%s %s)\n",
+			formatFloat(costs.Max.TotalCost, false), a.config.Currency,
+			formatFloat(costs.Max.TotalTime, roundUp), a.config.TimeUnit.Acronym))
+		sb.WriteString(fmt.Sprintf("  Min: %s %s (%s %s)",
+			formatFloat(costs.Min.TotalCost, false), a.config.Currency,
+			formatFloat(costs.Min.TotalTime, roundUp), a.config.TimeUnit.Acronym))
+		if costs.Max.FixedCost > 0 {
+			sb.WriteString(fmt.Sprintf("\n  Fixed: %s %s", formatFloat(costs.Max.FixedCost, false), a.config.Currency))
+		}
+	}
 
+	a.writePreviewWarnings(&sb)
 	a.preview.SetText(sb.String())
 }
 
-// onTaskChanged is called when a task is modified
-func (a *App) onTaskChanged(task *model.Task) {
-	// Task is already modified in place (it's a pointer to the task in the estimation)
-	a.hasUnsavedChanges = true
-	a.updateHeader()
-	a.updatePreview()
-}
-
-// onTaskAdded is called when a new task is added
-func (a *App) onTaskAdded(task *model.Task) {
-	// Task is already added by TaskTable.AddTask
-	a.hasUnsavedChanges = true
-	a.updateHeader()
-	a.updatePreview()
+// writePreviewWarnings appends the heuristic warnings on task ranges to the preview
+func (a *App) writePreviewWarnings(sb *strings.Builder) {
+	if warnings := a.estimation.Warnings(a.config); len(warnings) > 0 {
+		sb.WriteString("\n\n[orange]Warnings:[white]\n")
+		for _, warning := range warnings {
+			sb.WriteString(fmt.Sprintf("  - %s\n", tview.Escape(warning)))
+		}
+	}
 }
 
-// onTaskRemoved is called when a task is removed
-func (a *App) onTaskRemoved(taskID model.TaskID) {
-	// Task is already removed by TaskTable.deleteSelectedTask
-	a.hasUnsavedChanges = true
+// onEstimationChanged refreshes the header and the preview after an edit
+func (a *App) onEstimationChanged() {
 	a.updateHeader()
-	a.updatePreview()
+	a.schedulePreviewUpdate()
 }
 
 // save saves the estimation to file
 func (a *App) save() {
-	if err := a.store.SaveEstimation(a.filePath, a.estimation); err != nil {
+	if err := a.editor.Save(); err != nil {
 		// Show error in command bar
 		a.commandBar.SetText(fmt.Sprintf("[red]Error: Failed to save: %v[white]", err))
 		return
 	}
-	a.hasUnsavedChanges = false
 	a.updateHeader()
 }
 
+// export writes the in-memory estimation to the given file using the given format
+func (a *App) export(formatType string, path string) {
+	var result string
+
+	switch formatType {
+	case "markdown", "md":
+		result = format.NewMarkdownFormatter(a.config).Format(a.estimation)
+	case "json":
+		var err error
+		result, err = format.NewJSONFormatter(a.config).Format(a.estimation)
+		if err != nil {
+			a.commandBar.SetText(fmt.Sprintf("[red]Error: Failed to format estimation as JSON: %v[white]", err))
+			return
+		}
+	case "yaml", "yml":
+		var err error
+		result, err = format.NewYAMLFormatter(a.config).Format(a.estimation)
+		if err != nil {
+			a.commandBar.SetText(fmt.Sprintf("[red]Error: Failed to format estimation as YAML: %v[white]", err))
+			return
+		}
+	default:
+		a.commandBar.SetText(fmt.Sprintf("[red]Error: Unknown format '%s' (markdown, json, yaml)[white]", formatType))
+		return
+	}
+
+	// Through the store, to honor its root directory
+	if err := a.store.WriteFile(path, []byte(result)); err != nil {
+		a.commandBar.SetText(fmt.Sprintf("[red]Error: Failed to export: %v[white]", err))
+		return
+	}
+
+	a.exitCommandMode()
+
+	note := ""
+	if a.editor.HasUnsavedChanges() {
+		note = " (includes unsaved changes)"
+	}
+	a.footer.SetText(fmt.Sprintf("[green]Exported %s to %s%s[white]", formatType, tview.Escape(path), note))
+}
+
+// interrupt quits on Ctrl+C when the trap is disabled, asking for a second Ctrl+C to
+// discard unsaved changes
+func (a *App) interrupt() {
+	if a.editor.HasUnsavedChanges() && !a.ctrlCPressed {
+		a.ctrlCPressed = true
+		a.footer.SetText("[red]Unsaved changes — press Ctrl+C again to force quit[white]")
+		return
+	}
+	a.app.Stop()
+}
+
 // quit exits the application (now handled in handleCommand)
 func (a *App) quit() {
-	if a.hasUnsavedChanges {
+	if a.editor.HasUnsavedChanges() {
 		// This shouldn't be called anymore, but keep for safety
 		return
 	}
@@ -394,6 +721,10 @@ func (a *App) quit() {
 
 // editSelectedTask opens a modal to edit the selected task
 func (a *App) editSelectedTask() {
+	if a.refuseIfLocked() {
+		return
+	}
+
 	task := a.taskTable.GetSelectedTask()
 	if task == nil {
 		return
@@ -427,6 +758,11 @@ func (a *App) editSelectedTask() {
 	}
 	category := task.Category
 
+	// Get confidence options, the first one leaving the task without a level
+	confidenceOptions := append([]string{"(none)"}, a.config.GetTaskConfidenceLevels()...)
+	selectedConfidenceIndex := max(0, slices.Index(confidenceOptions, task.Confidence))
+	confidence := task.Confidence
+
 	form.AddInputField("Label:", label, 40, nil, func(text string) {
 		label = text
 	})
@@ -440,18 +776,25 @@ func (a *App) editSelectedTask() {
 		category = categoryIDs[index]
 	})
 
+	form.AddDropDown("Confidence:", confidenceOptions, selectedConfidenceIndex, func(option string, index int) {
+		confidence = ""
+		if index > 0 {
+			confidence = option
+		}
+	})
+
 	// Create estimation input fields
 	optimisticField := tview.NewInputField().
 		SetLabel("Optimistic:").
-		SetText(fmt.Sprintf("%.1f", optimisticVal)).
+		SetText(formatEstimate(optimisticVal)).
 		SetFieldWidth(10)
 	likelyField := tview.NewInputField().
 		SetLabel("Likely:").
-		SetText(fmt.Sprintf("%.1f", likelyVal)).
+		SetText(formatEstimate(likelyVal)).
 		SetFieldWidth(10)
 	pessimisticField := tview.NewInputField().
 		SetLabel("Pessimistic:").
-		SetText(fmt.Sprintf("%.1f", pessimisticVal)).
+		SetText(formatEstimate(pessimisticVal)).
 		SetFieldWidth(10)
 
 	// Add the input fields to the form
@@ -469,19 +812,23 @@ func (a *App) editSelectedTask() {
 
 	// Helper function to save and close
 	saveAndClose := func() {
-		task.Label = label
-		task.Description = description
-		task.Category = category
 		// Get values from fields (they may have been updated)
-		optimisticVal = parseFloat(optimisticField.GetText())
-		likelyVal = parseFloat(likelyField.GetText())
-		pessimisticVal = parseFloat(pessimisticField.GetText())
-		task.SetEstimations(optimisticVal, likelyVal, pessimisticVal, a.config.GetAutoEstimationMultiplier())
+		_, err := a.editor.UpdateTask(task.ID, editor.TaskFields{
+			Label:       label,
+			Description: description,
+			Category:    category,
+			Confidence:  confidence,
+			Optimistic:  parseFloat(optimisticField.GetText()),
+			Likely:      parseFloat(likelyField.GetText()),
+			Pessimistic: parseFloat(pessimisticField.GetText()),
+		})
+		if err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%v[white] ", err))
+			return
+		}
 
 		a.taskTable.Refresh()
-		a.hasUnsavedChanges = true
-		a.updateHeader()
-		a.updatePreview()
+		a.onEstimationChanged()
 		closeModal()
 	}
 
@@ -501,13 +848,7 @@ func (a *App) editSelectedTask() {
 	form.SetCancelFunc(closeModal)
 
 	// Center the form using a flex container
-	flex := tview.NewFlex().
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(nil, 0, 1, false).
-			AddItem(form, 22, 1, true).
-			AddItem(nil, 0, 1, false), 80, 1, true).
-		AddItem(nil, 0, 1, false)
+	flex := a.centerModal(form, 80, 24)
 
 	a.modalVisible = true
 	a.pages.AddPage("modal", flex, true, true)
@@ -516,6 +857,10 @@ func (a *App) editSelectedTask() {
 
 // addNewTask opens a dialog to add a new task
 func (a *App) addNewTask() {
+	if a.refuseIfLocked() {
+		return
+	}
+
 	// Create form
 	form := tview.NewForm()
 	form.SetBorder(true)
@@ -560,11 +905,16 @@ func (a *App) addNewTask() {
 		SetLabel("Pessimistic:").
 		SetText("0").
 		SetFieldWidth(10)
+	rangeField := tview.NewInputField().
+		SetLabel("Or range (min-max):").
+		SetPlaceholder("e.g. 3-8").
+		SetFieldWidth(10)
 
 	// Add the input fields to the form
 	form.AddFormItem(optimisticField)
 	form.AddFormItem(likelyField)
 	form.AddFormItem(pessimisticField)
+	form.AddFormItem(rangeField)
 
 	// Helper function to close modal
 	closeModal := func() {
@@ -575,19 +925,27 @@ func (a *App) addNewTask() {
 
 	// Helper function to add task and close
 	addAndClose := func() {
-		task := model.NewTask(label, category)
-		task.Description = description
-		// Get values from fields
-		optimisticVal := parseFloat(optimisticField.GetText())
-		likelyVal := parseFloat(likelyField.GetText())
-		pessimisticVal := parseFloat(pessimisticField.GetText())
-		task.SetEstimations(optimisticVal, likelyVal, pessimisticVal, a.config.GetAutoEstimationMultiplier())
-
-		a.taskTable.AddTask(task)
-		a.hasUnsavedChanges = true
-		a.updateHeader()
-		a.updatePreview()
+		// A range, when given, takes precedence over the three estimates
+		_, err := a.editor.AddTask(editor.TaskFields{
+			Label:       label,
+			Description: description,
+			Category:    category,
+			Optimistic:  parseFloat(optimisticField.GetText()),
+			Likely:      parseFloat(likelyField.GetText()),
+			Pessimistic: parseFloat(pessimisticField.GetText()),
+			Range:       rangeField.GetText(),
+		})
+		if err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%v[white] ", err))
+			return
+		}
+
+		a.taskTable.Refresh()
+		a.onEstimationChanged()
 		closeModal()
+
+		// Select the new task
+		a.taskTable.Select(a.taskTable.GetTaskCount(), 0)
 	}
 
 	// Add vim-style command handling for the form
@@ -606,13 +964,63 @@ func (a *App) addNewTask() {
 	form.SetCancelFunc(closeModal)
 
 	// Center the form using a flex container
-	flex := tview.NewFlex().
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(nil, 0, 1, false).
-			AddItem(form, 22, 1, true).
-			AddItem(nil, 0, 1, false), 80, 1, true).
-		AddItem(nil, 0, 1, false)
+	flex := a.centerModal(form, 80, 24)
+
+	a.modalVisible = true
+	a.pages.AddPage("modal", flex, true, true)
+	a.app.SetFocus(form)
+}
+
+// describeEstimation opens a dialog to edit the description of the estimation
+func (a *App) describeEstimation() {
+	if a.refuseIfLocked() {
+		return
+	}
+
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(" Describe Estimation ")
+	form.SetTitleAlign(tview.AlignCenter)
+
+	description := a.estimation.Description
+	form.AddTextArea("Description:", description, 60, 6, 0, func(text string) {
+		description = text
+	})
+
+	// Helper function to close modal
+	closeModal := func() {
+		a.modalVisible = false
+		a.pages.RemovePage("modal")
+		a.app.SetFocus(a.taskTable)
+	}
+
+	// Helper function to save the description and close
+	saveAndClose := func() {
+		if err := a.editor.Describe(strings.TrimSpace(description)); err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%v[white] ", err))
+			return
+		}
+
+		a.onEstimationChanged()
+		closeModal()
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Handle Escape to cancel
+		if event.Key() == tcell.KeyEscape {
+			closeModal()
+			return nil
+		}
+		return event
+	})
+
+	form.AddButton("Save", saveAndClose)
+	form.AddButton("Cancel (Esc)", closeModal)
+
+	form.SetCancelFunc(closeModal)
+
+	// Center the form using a flex container
+	flex := a.centerModal(form, 80, 13)
 
 	a.modalVisible = true
 	a.pages.AddPage("modal", flex, true, true)
@@ -629,29 +1037,9 @@ func (a *App) showHelp() {
 	helpView.SetTitleAlign(tview.AlignCenter)
 	helpView.SetTextAlign(tview.AlignLeft)
 
-	// Build help text with consistent formatting
-	helpText := `[yellow]Commands:[white]
-  :w         Save estimation
-  :q         Quit application
-  :q!        Force quit (discard changes)
-  :wq or :x  Save and quit
-
-[yellow]Task Operations:[white]
-  a          Add new task
-  e or i     Edit selected task
-  d          Delete selected task
-
-[yellow]Navigation:[white]
-  J          Move task down
-  K          Move task up
-  j/k/h/l    Navigate (vim-style)
-
-[yellow]Other:[white]
-  ?          Show this help
-
-[gray]Press Escape or Enter to close[white]`
-
-	helpView.SetText(helpText)
+	// The help is rendered from the key bindings, so that it matches the keys
+	text := helpText(append(appKeyBindings(), tableKeyBindings()...))
+	helpView.SetText(text)
 
 	// Handle key events to close
 	helpView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -665,19 +1053,96 @@ func (a *App) showHelp() {
 	})
 
 	// Center the help view using a flex container
-	flex := tview.NewFlex().
-		AddItem(nil, 0, 1, false).
-		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(nil, 0, 1, false).
-			AddItem(helpView, 18, 1, true).
-			AddItem(nil, 0, 1, false), 50, 1, true).
-		AddItem(nil, 0, 1, false)
+	// Borders included
+	flex := a.centerModal(helpView, 60, strings.Count(text, "\n")+3)
 
 	a.modalVisible = true
 	a.pages.AddPage("modal", flex, true, true)
 	a.app.SetFocus(helpView)
 }
 
+// previewBarWidth returns the width available for the category bars of the preview,
+// derived from the terminal width as the preview takes a quarter of the screen,
+// or all of it in the single column layout
+func (a *App) previewBarWidth() int {
+	// Borders and indentation of the preview
+	const padding = 4
+	if a.screenWidth < singleColumnWidth {
+		return max(0, a.screenWidth-padding)
+	}
+	return max(0, a.screenWidth/4-padding)
+}
+
+// updateLayout stacks the tasks table and the preview in a single column when the
+// terminal is narrower than singleColumnWidth, and puts them side by side otherwise
+func (a *App) updateLayout() {
+	if a.screenWidth < singleColumnWidth {
+		a.content.SetDirection(tview.FlexRow)
+		a.content.ResizeItem(a.taskTable, 0, 1)
+		a.content.ResizeItem(a.preview, 0, 1)
+		return
+	}
+	a.content.SetDirection(tview.FlexColumn)
+	a.content.ResizeItem(a.taskTable, 0, 3)
+	a.content.ResizeItem(a.preview, 0, 1)
+}
+
+// centerModal centers the content of a modal in a frame of the given size, shrunk to
+// the terminal when it is smaller so that forms and texts scroll instead of being clipped
+func (a *App) centerModal(content tview.Primitive, width, height int) *tview.Flex {
+	flex, resize := newModalFrame(content, width, height)
+
+	a.resizeModal = func() {
+		resize(a.screenWidth, a.screenHeight)
+	}
+	a.resizeModal()
+
+	return flex
+}
+
+// newModalFrame centers the content in a frame of the given size, returning the function
+// fitting the frame to the screen size, unknown when 0
+func newModalFrame(content tview.Primitive, width, height int) (*tview.Flex, func(screenWidth, screenHeight int)) {
+	column := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(content, height, 1, true).
+		AddItem(nil, 0, 1, false)
+	flex := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(column, width, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	resize := func(screenWidth, screenHeight int) {
+		column.ResizeItem(content, fitScreen(height, screenHeight), 1)
+		flex.ResizeItem(column, fitScreen(width, screenWidth), 1)
+	}
+
+	return flex, resize
+}
+
+// fitScreen returns the size bounded by the screen size, when known
+func fitScreen(size, screen int) int {
+	if screen <= 0 {
+		return size
+	}
+	return min(size, screen)
+}
+
+// renderBar renders a horizontal bar filling the given fraction of width cells,
+// using partial block characters for the remainder
+func renderBar(fraction float64, width int) string {
+	partials := []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+	eighths := int(math.Round(max(0, min(fraction, 1)) * float64(width*8)))
+	bar := strings.Repeat("█", eighths/8)
+	if remainder := eighths % 8; remainder > 0 {
+		bar += string(partials[remainder])
+	}
+	return bar
+}
+
+// formatFloat formats a value for display, rounded up if roundUp is true. The values
+// edited in the forms are stored unrounded.
 func formatFloat(value float64, roundUp bool) string {
 	if roundUp {
 		return fmt.Sprintf("%.0f", value)
@@ -685,6 +1150,12 @@ func formatFloat(value float64, roundUp bool) string {
 	return fmt.Sprintf("%.2f", value)
 }
 
+// formatEstimate formats an estimate for edition with all its digits, so that saving a
+// form left untouched keeps the exact stored value
+func formatEstimate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
 func parseFloat(s string) float64 {
 	var f float64
 	fmt.Sscanf(s, "%f", &f)
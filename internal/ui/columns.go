@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/model"
+	"github.com/bornholm/guesstimate/internal/stats"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// taskColumn is a column of the task table, rendering the cell of each task
+type taskColumn struct {
+	id     string
+	header string
+	// numeric columns are right aligned
+	numeric bool
+	// expansion is the share of the free width given to the column, 1 if unset
+	expansion int
+	// calculated columns can't be selected, their values not being editable
+	calculated bool
+
+	cell func(t *TaskTable, task *model.Task) (string, tcell.Color)
+}
+
+// taskColumns are the available columns of the task table
+var taskColumns = []taskColumn{
+	{id: "task", header: "Task", expansion: 2, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		// Task label, followed by the confidence level if any
+		if task.Confidence != "" {
+			return fmt.Sprintf("%s (%s)", task.Label, task.Confidence), tcell.ColorWhite
+		}
+		return task.Label, tcell.ColorWhite
+	}},
+	{id: "category", header: "Category", cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return t.config.GetTaskCategory(task.Category).Label, tcell.ColorWhite
+	}},
+	{id: "assignee", header: "Assignee", cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return task.Assignee, tcell.ColorWhite
+	}},
+	{id: "description", header: "Description", expansion: 2, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		line, _, _ := strings.Cut(task.Description, "\n")
+		return line, tcell.ColorWhite
+	}},
+	{id: "optimistic", header: "Optimistic", numeric: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return fmt.Sprintf("%.1f", t.config.DisplayEstimations(task.Estimations).Optimistic), tcell.ColorWhite
+	}},
+	{id: "likely", header: "Likely", numeric: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return fmt.Sprintf("%.1f", t.config.DisplayEstimations(task.Estimations).Likely), tcell.ColorWhite
+	}},
+	{id: "pessimistic", header: "Pessimistic", numeric: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return fmt.Sprintf("%.1f", t.config.DisplayEstimations(task.Estimations).Pessimistic), tcell.ColorWhite
+	}},
+	{id: "mean", header: "Mean", numeric: true, calculated: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		// Highlighted when clamped at the task cap
+		if task.IsCapped() {
+			return fmt.Sprintf("%.2f", task.WeightedMean()), tcell.ColorOrange
+		}
+		return fmt.Sprintf("%.2f", task.WeightedMean()), tcell.ColorGreen
+	}},
+	{id: "sd", header: "SD", numeric: true, calculated: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		return fmt.Sprintf("%.2f", task.StandardDeviationWith(t.config.GetEstimationModel())), tcell.ColorGreen
+	}},
+	{id: "cost", header: "Cost", numeric: true, calculated: true, cell: func(t *TaskTable, task *model.Task) (string, tcell.Color) {
+		if !t.config.HasCosts() {
+			return "-", tcell.ColorGray
+		}
+		return fmt.Sprintf("%.2f", stats.CalculateTaskCost(task, t.config)), tcell.ColorGreen
+	}},
+}
+
+// defaultTaskColumns are the columns of the task table when none is configured
+var defaultTaskColumns = []string{"task", "category", "optimistic", "likely", "pessimistic", "mean", "sd"}
+
+// resolveTaskColumns returns the columns with the given IDs, in order, or the default
+// columns if none is given
+func resolveTaskColumns(ids []string) ([]taskColumn, error) {
+	if len(ids) == 0 {
+		ids = defaultTaskColumns
+	}
+
+	columns := make([]taskColumn, 0, len(ids))
+	for _, id := range ids {
+		column, ok := findTaskColumn(strings.ToLower(strings.TrimSpace(id)))
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s', expected %s", id, strings.Join(taskColumnIDs(), ", "))
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// findTaskColumn returns the column with the given ID, if any
+func findTaskColumn(id string) (taskColumn, bool) {
+	for _, column := range taskColumns {
+		if column.id == id {
+			return column, true
+		}
+	}
+	return taskColumn{}, false
+}
+
+// taskColumnIDs returns the IDs of the available columns
+func taskColumnIDs() []string {
+	ids := make([]string, 0, len(taskColumns))
+	for _, column := range taskColumns {
+		ids = append(ids, column.id)
+	}
+	return ids
+}
+
+// headerCell returns the header cell of the column
+func (c taskColumn) headerCell() *tview.TableCell {
+	cell := tview.NewTableCell(c.header).
+		SetTextColor(tcell.ColorYellow).
+		SetSelectable(false).
+		SetExpansion(1)
+	if c.numeric {
+		cell.SetAlign(tview.AlignRight)
+	}
+	return cell
+}
+
+// taskCell returns the cell of the column for the task
+func (c taskColumn) taskCell(t *TaskTable, task *model.Task) *tview.TableCell {
+	text, color := c.cell(t, task)
+	cell := tview.NewTableCell(text).
+		SetTextColor(color).
+		SetReference(task.ID)
+	if c.expansion > 0 {
+		cell.SetExpansion(c.expansion)
+	}
+	if c.numeric {
+		cell.SetAlign(tview.AlignRight)
+	}
+	if c.calculated {
+		cell.SetSelectable(false)
+	}
+	return cell
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Help sections of the key bindings, in display order
+var helpSections = []string{"Task Operations", "Navigation", "Grabbed Task", "Preview", "Other"}
+
+// keyMode is the state of the editor a key binding applies to
+type keyMode int
+
+const (
+	// keyModeNormal is the default state, browsing the tasks
+	keyModeNormal keyMode = iota
+	// keyModeGrab is the state of a grabbed task being moved
+	keyModeGrab
+)
+
+// keyBinding associates keys with an action of the editor. The bindings are the single
+// source of the key handling and of the help, so that the help always matches the keys.
+type keyBinding struct {
+	section string
+	mode    keyMode
+	keys    []tcell.Key
+	runes   []rune
+	help    string
+
+	// Action run by the application, or by the task table
+	app   func(a *App)
+	table func(t *TaskTable)
+}
+
+// matches returns true if the event is one of the keys of the binding
+func (b keyBinding) matches(event *tcell.EventKey) bool {
+	if event.Key() == tcell.KeyRune {
+		return slices.Contains(b.runes, event.Rune())
+	}
+	return slices.Contains(b.keys, event.Key())
+}
+
+// label returns the keys of the binding as shown in the help, e.g. "Up/k"
+func (b keyBinding) label() string {
+	names := make([]string, 0, len(b.keys)+len(b.runes))
+	for _, key := range b.keys {
+		names = append(names, tcell.KeyNames[key])
+	}
+	for _, r := range b.runes {
+		if r == ' ' {
+			names = append(names, "Space")
+		} else {
+			names = append(names, string(r))
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// findKeyBinding returns the binding of the given mode matching the event, if any
+func findKeyBinding(bindings []keyBinding, mode keyMode, event *tcell.EventKey) (keyBinding, bool) {
+	for _, binding := range bindings {
+		if binding.mode == mode && binding.matches(event) {
+			return binding, true
+		}
+	}
+	return keyBinding{}, false
+}
+
+// appKeyBindings returns the key bindings handled by the application
+func appKeyBindings() []keyBinding {
+	return []keyBinding{
+		{section: "Task Operations", runes: []rune{'a'}, help: "Add new task", app: (*App).addNewTask},
+		{section: "Task Operations", runes: []rune{'e', 'i'}, help: "Edit selected task", app: (*App).editSelectedTask},
+		{section: "Task Operations", runes: []rune{'d'}, help: "Delete selected task", app: (*App).deleteSelectedTask},
+		{section: "Navigation", runes: []rune{'J'}, help: "Move task down", app: (*App).moveTaskDown},
+		{section: "Navigation", runes: []rune{'K'}, help: "Move task up", app: (*App).moveTaskUp},
+		{section: "Navigation", runes: []rune{' '}, help: "Grab task to move it", app: (*App).grabSelectedTask},
+		{section: "Grabbed Task", mode: keyModeGrab, keys: []tcell.Key{tcell.KeyEnter}, runes: []rune{' '}, help: "Drop task", app: (*App).dropGrabbedTask},
+		{section: "Grabbed Task", mode: keyModeGrab, keys: []tcell.Key{tcell.KeyEscape}, help: "Cancel move", app: (*App).cancelGrab},
+		{section: "Preview", runes: []rune{'+'}, help: "Raise target confidence", app: func(a *App) { a.adjustTargetConfidence(targetConfidenceStep) }},
+		{section: "Preview", runes: []rune{'-'}, help: "Lower target confidence", app: func(a *App) { a.adjustTargetConfidence(-targetConfidenceStep) }},
+		{section: "Other", runes: []rune{':'}, help: "Enter a command", app: (*App).startCommandMode},
+		{section: "Other", keys: []tcell.Key{tcell.KeyCtrlL}, runes: []rune{'r'}, help: "Refresh display", app: (*App).refresh},
+		{section: "Other", runes: []rune{'?'}, help: "Show this help", app: (*App).showHelp},
+	}
+}
+
+// tableKeyBindings returns the key bindings handled by the task table
+func tableKeyBindings() []keyBinding {
+	return []keyBinding{
+		{section: "Navigation", keys: []tcell.Key{tcell.KeyUp}, runes: []rune{'k'}, help: "Select previous task", table: func(t *TaskTable) { t.selectOffset(-1, 0) }},
+		{section: "Navigation", keys: []tcell.Key{tcell.KeyDown}, runes: []rune{'j'}, help: "Select next task", table: func(t *TaskTable) { t.selectOffset(1, 0) }},
+		{section: "Navigation", keys: []tcell.Key{tcell.KeyLeft}, runes: []rune{'h'}, help: "Select previous column", table: func(t *TaskTable) { t.selectOffset(0, -1) }},
+		{section: "Navigation", keys: []tcell.Key{tcell.KeyRight}, runes: []rune{'l'}, help: "Select next column", table: func(t *TaskTable) { t.selectOffset(0, 1) }},
+		{section: "Grabbed Task", mode: keyModeGrab, keys: []tcell.Key{tcell.KeyUp}, runes: []rune{'k'}, help: "Move task up", table: func(t *TaskTable) { t.moveGrabbed(-1) }},
+		{section: "Grabbed Task", mode: keyModeGrab, keys: []tcell.Key{tcell.KeyDown}, runes: []rune{'j'}, help: "Move task down", table: func(t *TaskTable) { t.moveGrabbed(1) }},
+	}
+}
+
+// commandHelp documents the commands of the command bar, in display order
+var commandHelp = []struct {
+	command string
+	help    string
+}{
+	{":w", "Save estimation"},
+	{":q", "Quit application"},
+	{":q!", "Force quit (discard changes)"},
+	{":wq or :x", "Save and quit"},
+	{":export", "Export (:export md report.md)"},
+	{":rename", "Rename estimation (:rename My project)"},
+	{":describe", "Edit estimation description"},
+	{":columns", "Set task columns (:columns task,mean,cost)"},
+	{"Up/Down", "Recall previous commands"},
+}
+
+// helpText renders the help of the commands and of the given key bindings, grouped
+// by section
+func helpText(bindings []keyBinding) string {
+	var sb strings.Builder
+
+	sb.WriteString("[yellow]Commands:[white]\n")
+	for _, command := range commandHelp {
+		sb.WriteString(fmt.Sprintf("  %-12s %s\n", command.command, command.help))
+	}
+
+	for _, section := range helpSections {
+		sb.WriteString(fmt.Sprintf("\n[yellow]%s:[white]\n", section))
+		for _, binding := range bindings {
+			if binding.section == section {
+				sb.WriteString(fmt.Sprintf("  %-12s %s\n", binding.label(), binding.help))
+			}
+		}
+	}
+
+	sb.WriteString("\n[gray]Press Escape or Enter to close, Up/Down to scroll[white]")
+
+	return sb.String()
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/bornholm/guesstimate/internal/store"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Picker lets the user choose the estimation to edit among the ones of a directory,
+// or create a new one
+type Picker struct {
+	app   *tview.Application
+	pages *tview.Pages
+	store store.Store
+	dir   string
+
+	// File chosen or created, empty if the picker was cancelled
+	file string
+
+	screenWidth  int
+	screenHeight int
+	resizeModal  func() // fits the visible modal to the terminal size
+}
+
+// NewPicker creates a picker of the estimations of the given directory
+func NewPicker(s store.Store, dir string) *Picker {
+	return &Picker{
+		app:   tview.NewApplication(),
+		pages: tview.NewPages(),
+		store: s,
+		dir:   dir,
+	}
+}
+
+// Run shows the picker and returns the path of the chosen or created estimation, or an
+// empty path if the user cancelled
+func (p *Picker) Run() (string, error) {
+	files, err := p.store.ListEstimations(p.dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list estimations: %w", err)
+	}
+
+	list := tview.NewList()
+	list.SetBorder(true)
+	list.SetTitle(fmt.Sprintf(" Estimations in %s ", p.dir))
+	list.SetTitleAlign(tview.AlignCenter)
+
+	for _, name := range files {
+		list.AddItem(name, p.describe(name), 0, nil)
+	}
+	list.AddItem("[green]+ New estimation[white]", "Create an estimation in this directory", 'n', p.showCreateForm)
+
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if index < len(files) {
+			p.file = filepath.Join(p.dir, files[index])
+			p.app.Stop()
+		}
+	})
+	list.SetDoneFunc(p.app.Stop)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'q' {
+			p.app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetText("[yellow]Enter[white] Edit  [yellow]n[white] New Estimation  [yellow]q/Esc[white] Quit")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	p.pages.AddPage("list", layout, true, true)
+	p.app.SetRoot(p.pages, true)
+
+	// Fit the modal to the terminal when it is resized
+	p.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		if width, height := screen.Size(); width != p.screenWidth || height != p.screenHeight {
+			p.screenWidth, p.screenHeight = width, height
+			if p.resizeModal != nil {
+				p.resizeModal()
+			}
+		}
+		return false
+	})
+
+	if err := p.app.Run(); err != nil {
+		return "", err
+	}
+
+	return p.file, nil
+}
+
+// describe returns the label of an estimation file, or the reason it can't be loaded
+func (p *Picker) describe(name string) string {
+	estimation, err := p.store.LoadEstimation(filepath.Join(p.dir, name))
+	if err != nil {
+		return fmt.Sprintf("[red]%s[white]", tview.Escape(err.Error()))
+	}
+	return fmt.Sprintf("%s (%d tasks)", tview.Escape(estimation.Label), len(estimation.Tasks))
+}
+
+// showCreateForm prompts for the label of a new estimation and creates it
+func (p *Picker) showCreateForm() {
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(" New Estimation ")
+	form.SetTitleAlign(tview.AlignCenter)
+
+	label := ""
+	form.AddInputField("Label:", "", 40, nil, func(text string) {
+		label = text
+	})
+
+	closeForm := func() {
+		p.pages.RemovePage("modal")
+		p.resizeModal = nil
+	}
+
+	create := func() {
+		label := strings.TrimSpace(label)
+		if label == "" {
+			form.SetTitle(" [red]label is required[white] ")
+			return
+		}
+
+		file := filepath.Join(p.dir, store.EstimationFileName(label))
+		if err := p.checkAvailable(file); err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%s[white] ", tview.Escape(err.Error())))
+			return
+		}
+
+		if _, err := p.store.CreateEstimation(file, label); err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%s[white] ", tview.Escape(err.Error())))
+			return
+		}
+
+		p.file = file
+		p.app.Stop()
+	}
+
+	form.AddButton("Create", create)
+	form.AddButton("Cancel (Esc)", closeForm)
+	form.SetCancelFunc(closeForm)
+
+	flex, resize := newModalFrame(form, 60, 7)
+	p.resizeModal = func() {
+		resize(p.screenWidth, p.screenHeight)
+	}
+	p.resizeModal()
+
+	p.pages.AddPage("modal", flex, true, true)
+	p.app.SetFocus(form)
+}
+
+// checkAvailable returns an error if an estimation, valid or not, already exists at the path
+func (p *Picker) checkAvailable(file string) error {
+	_, err := p.store.LoadEstimation(file)
+	if err == nil {
+		return fmt.Errorf("'%s' already exists", file)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
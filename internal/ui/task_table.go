@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"fmt"
-
 	"github.com/bornholm/guesstimate/internal/model"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -14,14 +12,13 @@ type TaskTable struct {
 
 	estimation *model.Estimation
 	config     *model.Config
-
-	// Callbacks
-	OnTaskChanged func(task *model.Task)
-	OnTaskAdded   func(task *model.Task)
-	OnTaskRemoved func(taskID model.TaskID)
+	columns    []taskColumn
 
 	// State
-	tasks []*model.Task
+	bindings   []keyBinding
+	tasks      []*model.Task
+	grabbed    int // Row of the grabbed task, 0 when no task is grabbed
+	grabOrigin int // Row the grabbed task was taken from
 }
 
 // NewTaskTable creates a new TaskTable
@@ -33,6 +30,8 @@ func NewTaskTable(estimation *model.Estimation, config *model.Config) *TaskTable
 		tasks:      estimation.GetOrderedTasks(),
 	}
 
+	t.columns, _ = resolveTaskColumns(defaultTaskColumns)
+
 	t.SetBorder(true)
 	t.SetTitle(" Tasks ")
 	t.SetSelectable(true, true)
@@ -47,20 +46,31 @@ func NewTaskTable(estimation *model.Estimation, config *model.Config) *TaskTable
 
 // setupColumns sets up the table columns
 func (t *TaskTable) setupColumns() {
-	headers := []string{"Task", "Category", "Optimistic", "Likely", "Pessimistic", "Mean", "SD"}
+	for i, column := range t.columns {
+		t.SetCell(0, i, column.headerCell())
+	}
+}
+
+// SetColumns replaces the columns of the table by the ones with the given IDs, in
+// order, or by the default columns if none is given
+func (t *TaskTable) SetColumns(ids []string) error {
+	columns, err := resolveTaskColumns(ids)
+	if err != nil {
+		return err
+	}
 
-	for i, header := range headers {
-		cell := tview.NewTableCell(header).
-			SetTextColor(tcell.ColorYellow).
-			SetSelectable(false).
-			SetExpansion(1)
+	row, col := t.GetSelection()
 
-		if i >= 2 {
-			cell = cell.SetAlign(tview.AlignRight)
-		}
+	t.columns = columns
+	t.Clear()
+	t.setupColumns()
+	t.populate()
 
-		t.SetCell(0, i, cell)
+	if t.GetRowCount() > 1 {
+		t.Select(max(1, min(row, t.GetRowCount()-1)), max(0, min(col, len(columns)-1)))
 	}
+
+	return nil
 }
 
 // populate fills the table with tasks
@@ -70,8 +80,9 @@ func (t *TaskTable) populate() {
 		t.RemoveRow(i)
 	}
 
-	// Refresh tasks from estimation
-	t.tasks = t.estimation.GetOrderedTasks()
+	// Refresh tasks from estimation, reusing the previous buffer
+	clear(t.tasks)
+	t.tasks = t.estimation.AppendOrderedTasks(t.tasks[:0])
 
 	// Add tasks
 	for i, task := range t.tasks {
@@ -81,202 +92,115 @@ func (t *TaskTable) populate() {
 
 // addTaskRow adds a row for a task
 func (t *TaskTable) addTaskRow(row int, task *model.Task) {
-	cat := t.config.GetTaskCategory(task.Category)
-	mean := task.WeightedMean()
-	sd := task.StandardDeviation()
-
-	// Task label (editable)
-	t.SetCell(row, 0, tview.NewTableCell(task.Label).
-		SetTextColor(tcell.ColorWhite).
-		SetExpansion(2).
-		SetReference(task.ID))
-
-	// Category
-	t.SetCell(row, 1, tview.NewTableCell(cat.Label).
-		SetTextColor(tcell.ColorWhite).
-		SetReference(task.ID))
-
-	// Optimistic
-	t.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%.1f", task.Estimations.Optimistic)).
-		SetTextColor(tcell.ColorWhite).
-		SetAlign(tview.AlignRight).
-		SetReference(task.ID))
-
-	// Likely
-	t.SetCell(row, 3, tview.NewTableCell(fmt.Sprintf("%.1f", task.Estimations.Likely)).
-		SetTextColor(tcell.ColorWhite).
-		SetAlign(tview.AlignRight).
-		SetReference(task.ID))
-
-	// Pessimistic
-	t.SetCell(row, 4, tview.NewTableCell(fmt.Sprintf("%.1f", task.Estimations.Pessimistic)).
-		SetTextColor(tcell.ColorWhite).
-		SetAlign(tview.AlignRight).
-		SetReference(task.ID))
-
-	// Mean (calculated)
-	t.SetCell(row, 5, tview.NewTableCell(fmt.Sprintf("%.2f", mean)).
-		SetTextColor(tcell.ColorGreen).
-		SetAlign(tview.AlignRight).
-		SetSelectable(false).
-		SetReference(task.ID))
-
-	// SD (calculated)
-	t.SetCell(row, 6, tview.NewTableCell(fmt.Sprintf("%.2f", sd)).
-		SetTextColor(tcell.ColorGreen).
-		SetAlign(tview.AlignRight).
-		SetSelectable(false).
-		SetReference(task.ID))
+	for i, column := range t.columns {
+		t.SetCell(row, i, column.taskCell(t, task))
+	}
+
+	// Highlight the grabbed task
+	if row == t.grabbed {
+		for col := 0; col < t.GetColumnCount(); col++ {
+			t.GetCell(row, col).SetBackgroundColor(tcell.ColorDarkSlateGray)
+		}
+	}
 }
 
 // setupKeyBindings sets up keyboard navigation
 func (t *TaskTable) setupKeyBindings() {
+	t.bindings = tableKeyBindings()
+
 	t.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyUp:
-			row, col := t.GetSelection()
-			if row > 1 {
-				t.Select(row-1, col)
-			}
-			return nil
-		case tcell.KeyDown:
-			row, col := t.GetSelection()
-			if row < t.GetRowCount()-1 {
-				t.Select(row+1, col)
-			}
-			return nil
-		case tcell.KeyLeft:
-			row, col := t.GetSelection()
-			if col > 0 {
-				t.Select(row, col-1)
-			}
-			return nil
-		case tcell.KeyRight:
-			row, col := t.GetSelection()
-			if col < 6 {
-				t.Select(row, col+1)
-			}
+		// Move the grabbed task instead of the selection
+		mode := keyModeNormal
+		if t.grabbed > 0 {
+			mode = keyModeGrab
+		}
+
+		if binding, ok := findKeyBinding(t.bindings, mode, event); ok {
+			binding.table(t)
 			return nil
-		case tcell.KeyRune:
-			switch event.Rune() {
-			case 'j':
-				row, col := t.GetSelection()
-				if row < t.GetRowCount()-1 {
-					t.Select(row+1, col)
-				}
-				return nil
-			case 'k':
-				row, col := t.GetSelection()
-				if row > 1 {
-					t.Select(row-1, col)
-				}
-				return nil
-			case 'h':
-				row, col := t.GetSelection()
-				if col > 0 {
-					t.Select(row, col-1)
-				}
-				return nil
-			case 'l':
-				row, col := t.GetSelection()
-				if col < 6 {
-					t.Select(row, col+1)
-				}
-				return nil
-			}
 		}
 
 		return event
 	})
 }
 
-// deleteSelectedTask deletes the currently selected task
-func (t *TaskTable) deleteSelectedTask() {
-	row, _ := t.GetSelection()
-
-	if row < 1 || row > len(t.tasks) {
+// selectOffset moves the selection by the given number of rows and columns, staying
+// within the task rows and the columns
+func (t *TaskTable) selectOffset(rows, cols int) {
+	if t.GetRowCount() <= 1 {
 		return
 	}
 
-	task := t.tasks[row-1]
-
-	// Remove task
-	t.estimation.RemoveTask(task.ID)
+	row, col := t.GetSelection()
+	row = max(1, min(row+rows, t.GetRowCount()-1))
+	col = max(0, min(col+cols, t.GetColumnCount()-1))
+	t.Select(row, col)
+}
 
-	// Notify listener
-	if t.OnTaskRemoved != nil {
-		t.OnTaskRemoved(task.ID)
+// Grab grabs the selected task so that it can be moved with j/k until released
+func (t *TaskTable) Grab() bool {
+	row, _ := t.GetSelection()
+	if row < 1 || row > len(t.tasks) {
+		return false
 	}
 
-	// Refresh table
-	t.populate()
+	t.grabbed = row
+	t.grabOrigin = row
+	t.addTaskRow(row, t.tasks[row-1])
 
-	// Adjust selection
-	if row >= t.GetRowCount() {
-		t.Select(t.GetRowCount()-1, 0)
-	} else {
-		t.Select(row, 0)
-	}
+	return true
 }
 
-// moveTaskUp moves the selected task up in the ordering
-func (t *TaskTable) moveTaskUp() {
-	row, _ := t.GetSelection()
+// IsGrabbing returns true if a task is currently grabbed
+func (t *TaskTable) IsGrabbing() bool {
+	return t.grabbed > 0
+}
 
-	if row < 2 || row > len(t.tasks) {
+// moveGrabbed moves the grabbed task in the display only, by the given offset
+func (t *TaskTable) moveGrabbed(offset int) {
+	row := t.grabbed + offset
+	if row < 1 || row > len(t.tasks) {
 		return
 	}
 
-	task := t.tasks[row-1]
-	t.estimation.MoveTask(task.ID, -1)
-
-	// Refresh table
-	t.populate()
+	t.tasks[t.grabbed-1], t.tasks[row-1] = t.tasks[row-1], t.tasks[t.grabbed-1]
 
-	// Notify listener
-	if t.OnTaskChanged != nil {
-		t.OnTaskChanged(task)
-	}
+	previous := t.grabbed
+	t.grabbed = row
+	t.addTaskRow(previous, t.tasks[previous-1])
+	t.addTaskRow(row, t.tasks[row-1])
 
-	// Restore selection
-	t.Select(row-1, 0)
+	_, col := t.GetSelection()
+	t.Select(row, col)
 }
 
-// moveTaskDown moves the selected task down in the ordering
-func (t *TaskTable) moveTaskDown() {
-	row, _ := t.GetSelection()
-
-	if row < 1 || row >= len(t.tasks) {
-		return
+// Release drops the grabbed task, returning it with the row it was grabbed from and the
+// row it was dropped at. The reordering is left to the caller, the table being refreshed
+// from the estimation afterwards.
+func (t *TaskTable) Release() (*model.Task, int, int) {
+	if t.grabbed == 0 {
+		return nil, 0, 0
 	}
 
+	row, origin := t.grabbed, t.grabOrigin
 	task := t.tasks[row-1]
-	t.estimation.MoveTask(task.ID, 1)
+	t.grabbed, t.grabOrigin = 0, 0
 
-	// Refresh table
-	t.populate()
+	return task, origin, row
+}
 
-	// Notify listener
-	if t.OnTaskChanged != nil {
-		t.OnTaskChanged(task)
+// CancelGrab puts the grabbed task back to its original row
+func (t *TaskTable) CancelGrab() {
+	if t.grabbed == 0 {
+		return
 	}
 
-	// Restore selection
-	t.Select(row+1, 0)
-}
+	origin := t.grabOrigin
+	t.grabbed, t.grabOrigin = 0, 0
 
-// AddTask adds a new task to the table
-func (t *TaskTable) AddTask(task *model.Task) {
-	t.estimation.AddTask(task)
+	// Refresh table from the untouched estimation ordering
 	t.populate()
-
-	// Notify listener
-	if t.OnTaskAdded != nil {
-		t.OnTaskAdded(task)
-	}
-
-	// Select the new task
-	t.Select(len(t.tasks), 0)
+	t.Select(origin, 0)
 }
 
 // GetSelectedTask returns the currently selected task
@@ -0,0 +1,20 @@
+// Package version exposes the build information of guesstimate,
+// injected at build time with -ldflags "-X".
+package version
+
+import "fmt"
+
+// Name is the name of the tool
+const Name = "guesstimate"
+
+// Build information (set via ldflags)
+var (
+	Version = "dev"
+	Commit  = "unknown"
+	Date    = "unknown"
+)
+
+// String returns a human readable description of the build
+func String() string {
+	return fmt.Sprintf("%s (commit: %s, built: %s)", Version, Commit, Date)
+}